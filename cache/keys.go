@@ -3,11 +3,17 @@ package cache
 import "fmt"
 
 const (
-	RewardsKeyPrefix    = "rewards"
-	ValidatorsKeyPrefix = "validators"
+	RewardsKeyPrefix     = "rewards"
+	WithdrawalsKeyPrefix = "withdrawals"
+	ValidatorsKeyPrefix  = "validators"
+	UnbondingsKeyPrefix  = "unbondings"
 
 	// {prefix}_{evm_address}
 	RewardsKeyFormat = "%s_%s"
+	// {prefix}_{evm_address}
+	UnbondingsKeyFormat = "%s_%s"
+	// {prefix}_{kind}_{evm_address}
+	WithdrawalsKeyFormat = "%s_%s_%s"
 	// {prefix}_{status}_{page.key}_{page.offset}_{page.limit}_{page.count_total}_{page.reverse}
 	ValidatorsKeyFormat = "%s_%s_%s_%s_%s_%s_%s"
 )
@@ -16,6 +22,14 @@ func RewardsKey(evmAddr string) string {
 	return fmt.Sprintf(RewardsKeyFormat, RewardsKeyPrefix, evmAddr)
 }
 
+func UnbondingsKey(evmAddr string) string {
+	return fmt.Sprintf(UnbondingsKeyFormat, UnbondingsKeyPrefix, evmAddr)
+}
+
+func WithdrawalsKey(kind, evmAddr string) string {
+	return fmt.Sprintf(WithdrawalsKeyFormat, WithdrawalsKeyPrefix, kind, evmAddr)
+}
+
 func ValidatorsKey(params map[string]string) string {
 	return fmt.Sprintf(
 		ValidatorsKeyFormat, ValidatorsKeyPrefix, params["status"], params["pagination.key"],
@@ -16,6 +16,8 @@ import (
 
 const (
 	DefaultCacheTTL = time.Hour
+	// UnbondingsCacheTTL is short as the state of the unbondings moves with time and the story api.
+	UnbondingsCacheTTL = time.Minute
 )
 
 const (
@@ -90,7 +92,11 @@ func GetRedisData(ctx context.Context, rdb *redis.Client, key string) (string, e
 }
 
 func SetRedisData(ctx context.Context, rdb *redis.Client, key string, data string) error {
-	return rdb.Set(ctx, key, data, DefaultCacheTTL).Err()
+	return SetRedisDataWithTTL(ctx, rdb, key, data, DefaultCacheTTL)
+}
+
+func SetRedisDataWithTTL(ctx context.Context, rdb *redis.Client, key string, data string, ttl time.Duration) error {
+	return rdb.Set(ctx, key, data, ttl).Err()
 }
 
 func InvalidateRedisData(ctx context.Context, rdb *redis.Client, key string) error {
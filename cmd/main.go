@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"math"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"github.com/BurntSushi/toml"
@@ -17,14 +19,50 @@ import (
 var (
 	home   = kingpin.Flag("home", "Home directory").Default(".").String()
 	config = kingpin.Flag("config", "Config file path").Default("config.toml").String()
+
+	serverCmd = kingpin.Command("server", "Run the API server and indexers").Default()
+
+	reindexCmd     = kingpin.Command("reindex", "Delete and rebuild the data of an indexer for a block range")
+	reindexIndexer = reindexCmd.Flag("indexer", "Indexer name").Required().String()
+	reindexFrom    = reindexCmd.Flag("from", "First block height to reindex").Required().Int64()
+	reindexTo      = reindexCmd.Flag("to", "Last block height to reindex").Required().Int64()
+
+	quarantineCmd        = kingpin.Command("quarantine", "Manage the blocks quarantined by the indexers")
+	quarantineListCmd    = quarantineCmd.Command("list", "List the blocks quarantined by an indexer")
+	quarantineListIdx    = quarantineListCmd.Flag("indexer", "Indexer name").Required().String()
+	quarantineReplayCmd  = quarantineCmd.Command("replay", "Reindex the blocks quarantined by an indexer and release them")
+	quarantineReplayIdx  = quarantineReplayCmd.Flag("indexer", "Indexer name").Required().String()
+	quarantineReplayFrom = quarantineReplayCmd.Flag("from", "First quarantined block height to replay").Default("0").Int64()
+	quarantineReplayTo   = quarantineReplayCmd.Flag("to", "Last quarantined block height to replay").Default(strconv.FormatInt(math.MaxInt64, 10)).Int64()
 )
 
 func main() {
-	kingpin.Parse()
+	cmd := kingpin.Parse()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	svrConfig := loadConfig()
+
+	switch cmd {
+	case serverCmd.FullCommand():
+		runServer(ctx, svrConfig)
+	case reindexCmd.FullCommand():
+		if err := server.Reindex(ctx, *home, svrConfig, *reindexIndexer, *reindexFrom, *reindexTo); err != nil {
+			log.Fatal().Err(err).Str("indexer", *reindexIndexer).Msg("reindex failed")
+		}
+	case quarantineListCmd.FullCommand():
+		if err := server.ListQuarantine(ctx, *home, svrConfig, *quarantineListIdx); err != nil {
+			log.Fatal().Err(err).Str("indexer", *quarantineListIdx).Msg("list quarantine failed")
+		}
+	case quarantineReplayCmd.FullCommand():
+		if err := server.ReplayQuarantine(ctx, *home, svrConfig, *quarantineReplayIdx, *quarantineReplayFrom, *quarantineReplayTo); err != nil {
+			log.Fatal().Err(err).Str("indexer", *quarantineReplayIdx).Msg("replay quarantine failed")
+		}
+	}
+}
+
+func loadConfig() *server.Config {
 	configFile, err := os.Open(*config)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to open config file")
@@ -40,7 +78,11 @@ func main() {
 		log.Fatal().Err(err).Msg("invalid config")
 	}
 
-	svr, err := server.NewServer(ctx, *home, &svrConfig)
+	return &svrConfig
+}
+
+func runServer(ctx context.Context, svrConfig *server.Config) {
+	svr, err := server.NewServer(ctx, *home, svrConfig)
 	if err != nil {
 		log.Fatal().Err(err).Msg("new story-staking-api server failed")
 	}
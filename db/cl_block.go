@@ -8,10 +8,10 @@ import (
 
 type CLBlock struct {
 	ID              uint64    `gorm:"primarykey"`
-	Height          int64     `gorm:"not null;column:height;index:idx_cl_block_height,unique"`
+	Height          int64     `gorm:"not null;column:height;index:idx_cl_block_height,unique;index:idx_cl_block_proposer_address_height,priority:2"`
 	Hash            string    `gorm:"not null;column:hash;index:idx_cl_block_hash,unique"`
-	ProposerAddress string    `gorm:"not null;column:proposer_address"`
-	Time            time.Time `gorm:"not null;column:time"`
+	ProposerAddress string    `gorm:"not null;column:proposer_address;index:idx_cl_block_proposer_address_height,priority:1"`
+	Time            time.Time `gorm:"not null;column:time;index:idx_cl_block_time"`
 }
 
 func (CLBlock) TableName() string {
@@ -46,3 +46,51 @@ func GetCLBlocks(db *gorm.DB, heights []int64) ([]*CLBlock, error) {
 
 	return clBlocks, nil
 }
+
+// CountCLBlocks returns the number of blocks since the given time and how many of them the proposer proposed.
+func CountCLBlocks(db *gorm.DB, proposer string, since time.Time) (int64, int64, error) {
+	var res struct {
+		Proposed int64
+		Total    int64
+	}
+	if err := db.Model(&CLBlock{}).
+		Select("COALESCE(SUM(CASE WHEN proposer_address = ? THEN 1 ELSE 0 END), 0) AS proposed, COUNT(*) AS total", proposer).
+		Where("time >= ?", since).
+		Scan(&res).Error; err != nil {
+		return 0, 0, err
+	}
+
+	return res.Proposed, res.Total, nil
+}
+
+func GetLatestProposedCLBlock(db *gorm.DB, proposer string) (*CLBlock, error) {
+	var clBlk CLBlock
+	if err := db.Where("proposer_address = ?", proposer).Order("height DESC").First(&clBlk).Error; err != nil {
+		return nil, err
+	}
+
+	return &clBlk, nil
+}
+
+// GetCLProposerCounts returns the number of blocks proposed by each of the proposers since the given height.
+func GetCLProposerCounts(db *gorm.DB, from int64, proposers ...string) (map[string]int64, error) {
+	var results []struct {
+		ProposerAddress string
+		Count           int64
+	}
+
+	if err := db.Model(&CLBlock{}).
+		Select("proposer_address, COUNT(*) AS count").
+		Where("proposer_address IN ? AND height >= ?", proposers, from).
+		Group("proposer_address").
+		Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(results))
+	for _, res := range results {
+		counts[res.ProposerAddress] = res.Count
+	}
+
+	return counts, nil
+}
@@ -52,4 +52,31 @@ func TestCLBlock(t *testing.T) {
 		require.Equal(t, "address3", latest.ProposerAddress)
 		require.Equal(t, time.Unix(300, 0).Unix(), latest.Time.Unix())
 	})
+
+	t.Run("TestProposerStats", func(t *testing.T) {
+		blocks := []*db.CLBlock{
+			{Height: 4, Hash: "hash4", ProposerAddress: "address1", Time: time.Unix(400, 0).UTC()},
+			{Height: 5, Hash: "hash5", ProposerAddress: "address2", Time: time.Unix(500, 0).UTC()},
+			{Height: 6, Hash: "hash6", ProposerAddress: "address1", Time: time.Unix(600, 0).UTC()},
+		}
+		require.NoError(t, db.BatchCreateCLBlocks(dbOperator, indexerName, blocks, 6))
+
+		proposed, total, err := db.CountCLBlocks(dbOperator, "address1", time.Unix(400, 0).UTC())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), proposed)
+		require.Equal(t, int64(3), total)
+
+		proposed, total, err = db.CountCLBlocks(dbOperator, "address4", time.Unix(400, 0).UTC())
+		require.NoError(t, err)
+		require.Equal(t, int64(0), proposed)
+		require.Equal(t, int64(3), total)
+
+		latest, err := db.GetLatestProposedCLBlock(dbOperator, "address1")
+		require.NoError(t, err)
+		require.Equal(t, int64(6), latest.Height)
+
+		counts, err := db.GetCLProposerCounts(dbOperator, 2, "address1", "address2", "address4")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"address1": 2, "address2": 2}, counts)
+	})
 }
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// clSlashingParamsID is the id of the single row of the slashing params.
+const clSlashingParamsID = 1
+
+// CLSlashingParams are the slashing params of the chain as last refreshed from the story api.
+type CLSlashingParams struct {
+	ID                      uint64 `gorm:"primarykey"`
+	SignedBlocksWindow      int64  `gorm:"not null;column:signed_blocks_window"`
+	MinSignedPerWindow      string `gorm:"not null;column:min_signed_per_window"` // Decimal fraction of the window
+	DowntimeJailDuration    string `gorm:"not null;column:downtime_jail_duration"`
+	SlashFractionDoubleSign string `gorm:"not null;column:slash_fraction_double_sign"`
+	SlashFractionDowntime   string `gorm:"not null;column:slash_fraction_downtime"`
+	RefreshedAt             int64  `gorm:"not null;column:refreshed_at"` // Unix timestamp
+}
+
+func (CLSlashingParams) TableName() string {
+	return "cl_slashing_params"
+}
+
+func UpsertCLSlashingParams(db *gorm.DB, params *CLSlashingParams) error {
+	params.ID = clSlashingParamsID
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		UpdateAll: true,
+	}).Create(params).Error
+}
+
+// GetCLSlashingParams returns the stored slashing params, gorm.ErrRecordNotFound if they were never refreshed.
+func GetCLSlashingParams(db *gorm.DB) (*CLSlashingParams, error) {
+	var params CLSlashingParams
+	if err := db.Where("id = ?", clSlashingParamsID).First(&params).Error; err != nil {
+		return nil, err
+	}
+
+	return &params, nil
+}
@@ -0,0 +1,48 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestCLSlashingParams(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLSlashingParams{}))
+
+	_, err = db.GetCLSlashingParams(dbOperator)
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	require.NoError(t, db.UpsertCLSlashingParams(dbOperator, &db.CLSlashingParams{
+		SignedBlocksWindow: 28800,
+		MinSignedPerWindow: "0.050000000000000000",
+		RefreshedAt:        100,
+	}))
+
+	// The params are kept in a single row updated by every refresh.
+	require.NoError(t, db.UpsertCLSlashingParams(dbOperator, &db.CLSlashingParams{
+		SignedBlocksWindow: 14400,
+		MinSignedPerWindow: "0.100000000000000000",
+		RefreshedAt:        200,
+	}))
+
+	params, err := db.GetCLSlashingParams(dbOperator)
+	require.NoError(t, err)
+	require.Equal(t, int64(14400), params.SignedBlocksWindow)
+	require.Equal(t, "0.100000000000000000", params.MinSignedPerWindow)
+	require.Equal(t, int64(200), params.RefreshedAt)
+
+	var count int64
+	require.NoError(t, dbOperator.Model(&db.CLSlashingParams{}).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+}
@@ -5,13 +5,15 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CLStakingEvent struct {
 	ID          uint64 `gorm:"primarykey"`
 	ELTxHash    string `gorm:"not null;column:el_tx_hash;index:idx_cl_staking_event_el_tx_hash_event_type,priority:1"`
 	EventType   string `gorm:"not null;column:event_type;index:idx_cl_staking_event_el_tx_hash_event_type,priority:2"`
-	BlockHeight int64  `gorm:"not null;column:block_height;index:idx_cl_staking_event_block_height"`
+	BlockHeight int64  `gorm:"not null;column:block_height;index:idx_cl_staking_event_block_height;index:idx_cl_staking_event_block_height_event_index,priority:1,unique"`
+	EventIndex  int    `gorm:"not null;default:0;column:event_index;index:idx_cl_staking_event_block_height_event_index,priority:2,unique"` // Index among the staking events of the block
 	StatusOK    bool   `gorm:"not null;column:status_ok"`
 	ErrorCode   string `gorm:"not null;column:error_code"`
 	Amount      string `gorm:"not null;column:amount"`
@@ -26,9 +28,17 @@ func (CLStakingEvent) TableName() string {
 	return "cl_staking_events"
 }
 
-func BatchCreateCLStakingEvents(db *gorm.DB, indexer string, events []*CLStakingEvent, height int64) error {
+// BatchCreateCLStakingEvents stores the staking events along with the validator penalties found in the same blocks.
+func BatchCreateCLStakingEvents(db *gorm.DB, indexer string, events []*CLStakingEvent, penalties []*CLValidatorPenalty, height int64) error {
 	return db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.CreateInBatches(events, 100).Error; err != nil {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "block_height"}, {Name: "event_index"}},
+			UpdateAll: true,
+		}).CreateInBatches(events, 100).Error; err != nil {
+			return err
+		}
+
+		if err := createCLValidatorPenalties(tx, penalties); err != nil {
 			return err
 		}
 
@@ -36,7 +46,7 @@ func BatchCreateCLStakingEvents(db *gorm.DB, indexer string, events []*CLStaking
 	})
 }
 
-func GetSuccessfulCLStakingEventsAfter(db *gorm.DB, eventTypes []string, blockHeight int64) ([]*CLSuccessfulStakingEvent, error) {
+func GetSuccessfulCLStakingEvents(db *gorm.DB, eventTypes []string, from, to int64) ([]*CLSuccessfulStakingEvent, error) {
 	var events []*CLSuccessfulStakingEvent
 
 	if err := db.
@@ -45,7 +55,7 @@ func GetSuccessfulCLStakingEventsAfter(db *gorm.DB, eventTypes []string, blockHe
 		Select("e.*, b.time AS block_time").
 		Where("e.event_type IN (?)", eventTypes).
 		Where("e.status_ok = ?", true).
-		Where("e.block_height > ?", blockHeight).
+		Where("e.block_height >= ? AND e.block_height <= ?", from, to).
 		Scan(&events).Error; errors.Is(err, gorm.ErrRecordNotFound) {
 		return events, nil
 	} else if err != nil {
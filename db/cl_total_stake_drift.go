@@ -0,0 +1,35 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CLTotalStakeDrift is a difference found by a reconciliation between the running total stake and the bonded tokens
+// of the staking pool at the same block. The tokens of the unbonded validators are a known offset, they are staked
+// but left out of the bonded tokens.
+type CLTotalStakeDrift struct {
+	ID                      uint64 `gorm:"primarykey"`
+	BlockHeight             int64  `gorm:"not null;column:block_height;index:idx_cl_total_stake_drift_block_height,unique"`
+	BlockTime               int64  `gorm:"not null;column:block_time"`                // Unix timestamp
+	TotalStakeAmount        int64  `gorm:"not null;column:total_stake_amount"`        // Running total before the correction, in gwei
+	BondedTokens            int64  `gorm:"not null;column:bonded_tokens"`             // In gwei
+	UnbondedValidatorTokens int64  `gorm:"not null;column:unbonded_validator_tokens"` // Jailed or out of the active set, in gwei
+	Drift                   int64  `gorm:"not null;column:drift"`                     // Bonded tokens plus the offset minus running total
+	Corrected               bool   `gorm:"not null;column:corrected"`                 // Whether the running total is overwritten with the bonded tokens plus the offset
+}
+
+func (CLTotalStakeDrift) TableName() string {
+	return "cl_total_stake_drifts"
+}
+
+func createCLTotalStakeDrift(tx *gorm.DB, drift *CLTotalStakeDrift) error {
+	if drift == nil {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "block_height"}},
+		UpdateAll: true,
+	}).Create(drift).Error
+}
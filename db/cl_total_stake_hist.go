@@ -70,8 +70,13 @@ func UpsertCLGenesisTotalStakeHist(db *gorm.DB, indexer string, stake *CLTotalSt
 	})
 }
 
-func BatchUpsertCLTotalStakeHists(db *gorm.DB, indexer string, stakes []*CLTotalStakeHist) error {
+// BatchUpsertCLTotalStakeHists stores the total stakes along with the drift found by a reconciliation, if any.
+func BatchUpsertCLTotalStakeHists(db *gorm.DB, indexer string, stakes []*CLTotalStakeHist, drift *CLTotalStakeDrift, height int64) error {
 	return db.Transaction(func(tx *gorm.DB) error {
+		if err := createCLTotalStakeDrift(tx, drift); err != nil {
+			return err
+		}
+
 		if err := tx.Clauses(clause.OnConflict{
 			Columns: []clause.Column{{Name: "updated_at_time"}},
 			DoUpdates: clause.Assignments(map[string]interface{}{
@@ -81,6 +86,6 @@ func BatchUpsertCLTotalStakeHists(db *gorm.DB, indexer string, stakes []*CLTotal
 			return err
 		}
 
-		return nil
+		return UpdateIndexPoint(tx, indexer, height)
 	})
 }
@@ -0,0 +1,90 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+func TestCLTotalStakeHist(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLBlock{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorPenalty{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLTotalStakeHist{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLTotalStakeDrift{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	for _, name := range []string{"cl_block", "cl_staking_event", "cl_total_stake_hist"} {
+		require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{Indexer: name}))
+	}
+
+	blocks := make([]*db.CLBlock, 0)
+	for i := int64(1); i <= 10; i++ {
+		blocks = append(blocks, &db.CLBlock{Height: i, Hash: fmt.Sprintf("hash%d", i), Time: time.Unix(i*100, 0).UTC()})
+	}
+	require.NoError(t, db.BatchCreateCLBlocks(dbOperator, "cl_block", blocks, 10))
+
+	require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, "cl_staking_event", nil, []*db.CLValidatorPenalty{
+		{ConsensusAddress: "CONS1", Kind: indexer.PenaltyKindSlash, Amount: "5", BlockHeight: 3},
+		{ConsensusAddress: "CONS1", Kind: indexer.PenaltyKindJail, Amount: "0", BlockHeight: 3, EventIndex: 1},
+		{ConsensusAddress: "CONS2", Kind: indexer.PenaltyKindSlash, Amount: "7", BlockHeight: 3, EventIndex: 2},
+		{ConsensusAddress: "CONS2", Kind: indexer.PenaltyKindSlash, Amount: "50", BlockHeight: 8},
+	}, 8))
+
+	t.Run("GetCLPenaltyAmounts", func(t *testing.T) {
+		amounts, err := db.GetCLPenaltyAmounts(dbOperator, indexer.PenaltyKindSlash, 1, 5)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(amounts))
+		require.Equal(t, int64(3), amounts[0].BlockHeight)
+		require.Equal(t, int64(300), amounts[0].BlockTime.Unix())
+		require.Equal(t, "12", amounts[0].Amount)
+	})
+
+	t.Run("BatchUpsertCLTotalStakeHists", func(t *testing.T) {
+		require.NoError(t, db.BatchUpsertCLTotalStakeHists(dbOperator, "cl_total_stake_hist", []*db.CLTotalStakeHist{
+			{TotalStakeAmount: 1024, UpdatedAtBlock: 3, UpdatedAtTime: 300},
+		}, nil, 5))
+
+		// The correction of a reconciliation is stored along with the drift found.
+		require.NoError(t, db.BatchUpsertCLTotalStakeHists(dbOperator, "cl_total_stake_hist", []*db.CLTotalStakeHist{
+			{TotalStakeAmount: 2048, UpdatedAtBlock: 10, UpdatedAtTime: 1000},
+		}, &db.CLTotalStakeDrift{
+			BlockHeight:             10,
+			BlockTime:               1000,
+			TotalStakeAmount:        1000,
+			BondedTokens:            2000,
+			UnbondedValidatorTokens: 48,
+			Drift:                   1048,
+			Corrected:               true,
+		}, 10))
+
+		latest, err := db.GetLatestCLTotalStakeHist(dbOperator)
+		require.NoError(t, err)
+		require.Equal(t, int64(2048), latest.TotalStakeAmount)
+
+		var drifts []*db.CLTotalStakeDrift
+		require.NoError(t, dbOperator.Find(&drifts).Error)
+		require.Equal(t, 1, len(drifts))
+		require.Equal(t, int64(1048), drifts[0].Drift)
+		require.Equal(t, int64(48), drifts[0].UnbondedValidatorTokens)
+		require.True(t, drifts[0].Corrected)
+
+		indexPoint, err := db.GetIndexPoint(dbOperator, "cl_total_stake_hist")
+		require.NoError(t, err)
+		require.Equal(t, int64(10), indexPoint.BlockHeight)
+	})
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CLValidator maps the consensus address of a validator of the active set to its EVM address, along with
+// its voting power the last time it was in the active set.
+type CLValidator struct {
+	ID               uint64 `gorm:"primarykey"`
+	ConsensusAddress string `gorm:"not null;column:consensus_address;index:idx_cl_validator_consensus_address,unique"` // Upper case hex, same as the proposer address of blocks
+	EVMAddress       string `gorm:"not null;column:evm_address;index:idx_cl_validator_evm_address"`                    // To lower case
+	VotingPower      int64  `gorm:"not null;column:voting_power"`
+	LastActiveHeight int64  `gorm:"not null;column:last_active_height;index:idx_cl_validator_last_active_height"`
+}
+
+func (CLValidator) TableName() string {
+	return "cl_validators"
+}
+
+func upsertCLValidators(tx *gorm.DB, validators []*CLValidator) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "consensus_address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"voting_power", "last_active_height"}),
+	}).CreateInBatches(validators, 100).Error
+}
+
+func GetCLValidatorByEVMAddress(db *gorm.DB, evmAddr string) (*CLValidator, error) {
+	var validator CLValidator
+	if err := db.Where("evm_address = ?", evmAddr).Order("last_active_height DESC").First(&validator).Error; err != nil {
+		return nil, err
+	}
+
+	return &validator, nil
+}
+
+// GetCLValidatorsByEVMAddress returns the validators of the EVM addresses keyed by EVM address.
+func GetCLValidatorsByEVMAddress(db *gorm.DB, evmAddrs ...string) (map[string]*CLValidator, error) {
+	var validators []*CLValidator
+	if err := db.Where("evm_address IN ?", evmAddrs).Order("last_active_height ASC").Find(&validators).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*CLValidator, len(validators))
+	for _, v := range validators {
+		res[v.EVMAddress] = v
+	}
+
+	return res, nil
+}
+
+// GetCLActiveSet returns the height the latest active set known is seen at and its total voting power.
+func GetCLActiveSet(db *gorm.DB) (int64, int64, error) {
+	var res struct {
+		Height           int64
+		TotalVotingPower int64
+	}
+	if err := db.Model(&CLValidator{}).
+		Select("COALESCE(MAX(last_active_height), 0) AS height, COALESCE(SUM(voting_power), 0) AS total_voting_power").
+		Where("last_active_height = (SELECT MAX(last_active_height) FROM cl_validators)").
+		Scan(&res).Error; err != nil {
+		return 0, 0, err
+	}
+
+	return res.Height, res.TotalVotingPower, nil
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CLValidatorPenalty is a slash or a jail of a validator emitted by the slashing module of the consensus layer.
+type CLValidatorPenalty struct {
+	ID               uint64 `gorm:"primarykey"`
+	ConsensusAddress string `gorm:"not null;column:consensus_address;index:idx_cl_validator_penalty_consensus_address_block_height,priority:1"` // Upper case hex
+	Kind             string `gorm:"not null;column:kind"`                                                                                       // slash | jail
+	Reason           string `gorm:"not null;column:reason"`                                                                                     // double_sign | missing_signature, empty if not given
+	Power            int64  `gorm:"not null;column:power"`                                                                                      // Consensus power before the slash
+	Amount           string `gorm:"not null;default:0;column:amount;type:numeric"`                                                              // Burned tokens of the slash in gwei
+	BlockHeight      int64  `gorm:"not null;column:block_height;index:idx_cl_validator_penalty_consensus_address_block_height,priority:2;index:idx_cl_validator_penalty_block_height_event_index,priority:1,unique"`
+	EventIndex       int    `gorm:"not null;column:event_index;index:idx_cl_validator_penalty_block_height_event_index,priority:2,unique"` // Index among the penalties of the block
+}
+
+func (CLValidatorPenalty) TableName() string {
+	return "cl_validator_penalties"
+}
+
+// CLValidatorPenaltyDetail is a penalty along with the EVM address of the validator and the time of the block.
+type CLValidatorPenaltyDetail struct {
+	CLValidatorPenalty
+	EVMAddress string    `gorm:"column:evm_address"` // Empty if the validator has not been seen in the active set
+	BlockTime  time.Time `gorm:"column:block_time"`
+}
+
+func createCLValidatorPenalties(tx *gorm.DB, penalties []*CLValidatorPenalty) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "block_height"}, {Name: "event_index"}},
+		UpdateAll: true,
+	}).CreateInBatches(penalties, 100).Error
+}
+
+func penaltyDetailQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("cl_validator_penalties AS p").
+		Joins("INNER JOIN cl_blocks AS b ON p.block_height = b.height").
+		Joins("LEFT JOIN cl_validators AS v ON p.consensus_address = v.consensus_address").
+		Select("p.*, COALESCE(v.evm_address, '') AS evm_address, b.time AS block_time")
+}
+
+// GetCLValidatorPenalties returns the penalties of the validator, latest first.
+func GetCLValidatorPenalties(db *gorm.DB, consAddr string) ([]*CLValidatorPenaltyDetail, error) {
+	var penalties []*CLValidatorPenaltyDetail
+	if err := penaltyDetailQuery(db).
+		Where("p.consensus_address = ?", consAddr).
+		Order("p.block_height DESC, p.event_index DESC").
+		Scan(&penalties).Error; err != nil {
+		return nil, err
+	}
+
+	return penalties, nil
+}
+
+// DelegationWindow is a span of consensus layer heights an address delegated to a validator over.
+type DelegationWindow struct {
+	Validator string // EVM address
+	Since     int64
+	Until     int64 // 0 while the address still delegates to the validator
+}
+
+// GetDelegatorCLValidatorPenalties returns the penalties of the validators within the windows the address delegated
+// to them over, both ends included, latest first.
+func GetDelegatorCLValidatorPenalties(db *gorm.DB, windows []*DelegationWindow) ([]*CLValidatorPenaltyDetail, error) {
+	if len(windows) == 0 {
+		return []*CLValidatorPenaltyDetail{}, nil
+	}
+
+	conds := make([]string, 0, len(windows))
+	args := make([]interface{}, 0, 3*len(windows))
+	for _, w := range windows {
+		if w.Until > 0 {
+			conds = append(conds, "(v.evm_address = ? AND p.block_height >= ? AND p.block_height <= ?)")
+			args = append(args, w.Validator, w.Since, w.Until)
+		} else {
+			conds = append(conds, "(v.evm_address = ? AND p.block_height >= ?)")
+			args = append(args, w.Validator, w.Since)
+		}
+	}
+
+	var penalties []*CLValidatorPenaltyDetail
+	if err := penaltyDetailQuery(db).
+		Where(strings.Join(conds, " OR "), args...).
+		Order("p.block_height DESC, p.event_index DESC").
+		Scan(&penalties).Error; err != nil {
+		return nil, err
+	}
+
+	return penalties, nil
+}
+
+// GetCLValidatorPenaltiesOf returns the penalties of the kind of the validators by evm address, in chain order.
+func GetCLValidatorPenaltiesOf(db *gorm.DB, valAddrs []string, kind string) ([]*CLValidatorPenaltyDetail, error) {
+	var penalties []*CLValidatorPenaltyDetail
+	if err := penaltyDetailQuery(db).
+		Where("v.evm_address IN ? AND p.kind = ?", valAddrs, kind).
+		Order("p.block_height ASC, p.event_index ASC").
+		Scan(&penalties).Error; err != nil {
+		return nil, err
+	}
+
+	return penalties, nil
+}
+
+// CLPenaltyAmount is the stake burned by the penalties of a kind in a block.
+type CLPenaltyAmount struct {
+	BlockHeight int64
+	BlockTime   time.Time
+	Amount      string // In gwei
+}
+
+// GetCLPenaltyAmounts returns the stake burned by the penalties of the kind in each block of [from, to] with
+// penalties, in block order.
+func GetCLPenaltyAmounts(db *gorm.DB, kind string, from, to int64) ([]*CLPenaltyAmount, error) {
+	var amounts []*CLPenaltyAmount
+	if err := db.Table("cl_validator_penalties AS p").
+		Joins("INNER JOIN cl_blocks AS b ON p.block_height = b.height").
+		Select("p.block_height AS block_height, b.time AS block_time, SUM(p.amount) AS amount").
+		Where("p.kind = ? AND p.block_height >= ? AND p.block_height <= ?", kind, from, to).
+		Group("p.block_height, b.time").
+		Order("p.block_height ASC").
+		Scan(&amounts).Error; err != nil {
+		return nil, err
+	}
+
+	return amounts, nil
+}
+
+// CLValidatorPenaltyAmount is the stake burned by the penalties of a kind of a validator in a block.
+type CLValidatorPenaltyAmount struct {
+	BlockHeight      int64
+	BlockTime        time.Time
+	ConsensusAddress string
+	Validator        string // EVM address, empty if the validator is not indexed
+	Amount           string // In gwei
+}
+
+// GetCLValidatorPenaltyAmounts returns the stake burned by the penalties of the kind of each validator in each
+// block of [from, to] with penalties, in block order.
+func GetCLValidatorPenaltyAmounts(db *gorm.DB, kind string, from, to int64) ([]*CLValidatorPenaltyAmount, error) {
+	var amounts []*CLValidatorPenaltyAmount
+	if err := db.Table("cl_validator_penalties AS p").
+		Joins("INNER JOIN cl_blocks AS b ON p.block_height = b.height").
+		Joins("LEFT JOIN cl_validators AS v ON p.consensus_address = v.consensus_address").
+		Select("p.block_height AS block_height, b.time AS block_time, p.consensus_address AS consensus_address, COALESCE(v.evm_address, '') AS validator, SUM(p.amount) AS amount").
+		Where("p.kind = ? AND p.block_height >= ? AND p.block_height <= ?", kind, from, to).
+		Group("p.block_height, b.time, p.consensus_address, v.evm_address").
+		Order("p.block_height ASC, p.consensus_address ASC").
+		Scan(&amounts).Error; err != nil {
+		return nil, err
+	}
+
+	return amounts, nil
+}
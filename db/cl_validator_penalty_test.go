@@ -0,0 +1,116 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+func TestCLValidatorPenalty(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLBlock{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidator{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorVote{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorPenalty{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	for _, name := range []string{"cl_block", "cl_staking_event", "el_staking_event", "cl_validator_vote"} {
+		require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{Indexer: name}))
+	}
+
+	blocks := make([]*db.CLBlock, 0)
+	for i := int64(1); i <= 10; i++ {
+		blocks = append(blocks, &db.CLBlock{Height: i, Hash: fmt.Sprintf("hash%d", i), Time: time.Unix(i*100, 0).UTC()})
+	}
+	require.NoError(t, db.BatchCreateCLBlocks(dbOperator, "cl_block", blocks, 10))
+
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, "cl_validator_vote", &db.CLValidatorVoteBatch{
+		Validators: []*db.CLValidator{
+			{ConsensusAddress: "CONS1", EVMAddress: "0xval1", VotingPower: 10, LastActiveHeight: 1},
+			{ConsensusAddress: "CONS2", EVMAddress: "0xval2", VotingPower: 10, LastActiveHeight: 1},
+		},
+	}, 1))
+
+	// The delegator stakes to validator 1 at height 4, validator 2 is never delegated to.
+	require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, "el_staking_event", []*db.ELStakingEvent{
+		{TxHash: "tx_hash1", BlockHeight: 3, EventType: indexer.TypeStake, Address: "0xdel1", DstValidatorAddress: "0xval1"},
+	}, 3))
+
+	require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, "cl_staking_event",
+		[]*db.CLStakingEvent{
+			{ELTxHash: "tx_hash1", EventType: indexer.TypeStake, BlockHeight: 4, StatusOK: true, Amount: "1024"},
+		},
+		[]*db.CLValidatorPenalty{
+			{ConsensusAddress: "CONS1", Kind: indexer.PenaltyKindSlash, Reason: "missing_signature", Power: 10, Amount: "5", BlockHeight: 2},
+			{ConsensusAddress: "CONS1", Kind: indexer.PenaltyKindSlash, Reason: "missing_signature", Power: 10, Amount: "5", BlockHeight: 6},
+			{ConsensusAddress: "CONS1", Kind: indexer.PenaltyKindJail, Reason: "missing_signature", Power: 10, Amount: "0", BlockHeight: 6, EventIndex: 1},
+			{ConsensusAddress: "CONS2", Kind: indexer.PenaltyKindSlash, Reason: "double_sign", Power: 10, Amount: "50", BlockHeight: 7},
+		}, 7))
+
+	penalties, err := db.GetCLValidatorPenalties(dbOperator, "CONS1")
+	require.NoError(t, err)
+	require.Equal(t, 3, len(penalties))
+	require.Equal(t, indexer.PenaltyKindJail, penalties[0].Kind)
+	require.Equal(t, "0xval1", penalties[0].EVMAddress)
+	require.Equal(t, int64(600), penalties[0].BlockTime.Unix())
+	require.Equal(t, int64(2), penalties[2].BlockHeight)
+
+	penalties, err = db.GetDelegatorCLValidatorPenalties(dbOperator, []*db.DelegationWindow{{Validator: "0xval1", Since: 4}})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(penalties))
+	for _, p := range penalties {
+		require.Equal(t, "CONS1", p.ConsensusAddress)
+		require.Equal(t, int64(6), p.BlockHeight)
+	}
+
+	// The windows are bounded by the end of the delegation, both ends included.
+	penalties, err = db.GetDelegatorCLValidatorPenalties(dbOperator, []*db.DelegationWindow{
+		{Validator: "0xval1", Since: 2, Until: 5},
+		{Validator: "0xval2", Since: 7, Until: 7},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(penalties))
+	require.Equal(t, "CONS2", penalties[0].ConsensusAddress)
+	require.Equal(t, int64(2), penalties[1].BlockHeight)
+
+	penalties, err = db.GetDelegatorCLValidatorPenalties(dbOperator, nil)
+	require.NoError(t, err)
+	require.Empty(t, penalties)
+
+	t.Run("GetCLValidatorPenaltyAmounts", func(t *testing.T) {
+		amounts, err := db.GetCLValidatorPenaltyAmounts(dbOperator, indexer.PenaltyKindSlash, 2, 7)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(amounts))
+		require.Equal(t, "0xval1", amounts[0].Validator)
+		require.Equal(t, int64(2), amounts[0].BlockHeight)
+		require.Equal(t, "0xval2", amounts[2].Validator)
+		require.Equal(t, "50", amounts[2].Amount)
+		require.Equal(t, int64(700), amounts[2].BlockTime.Unix())
+	})
+
+	t.Run("reindex", func(t *testing.T) {
+		require.NoError(t, db.ReplaceCLStakingEvents(dbOperator, 5, 7, nil, []*db.CLValidatorPenalty{
+			{ConsensusAddress: "CONS2", Kind: indexer.PenaltyKindSlash, Reason: "double_sign", Power: 10, Amount: "50", BlockHeight: 7},
+		}))
+
+		penalties, err := db.GetCLValidatorPenalties(dbOperator, "CONS1")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(penalties))
+		require.Equal(t, int64(2), penalties[0].BlockHeight)
+	})
+}
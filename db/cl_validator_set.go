@@ -0,0 +1,87 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CLValidatorSetSnapshot is a validator of the active set at a block the set or the voting powers changed at,
+// the set stays the same until the next snapshot height.
+type CLValidatorSetSnapshot struct {
+	ID               uint64    `gorm:"primarykey"`
+	Height           int64     `gorm:"not null;column:height;index:idx_cl_validator_set_snapshot_height_consensus_address,priority:1,unique;index:idx_cl_validator_set_snapshot_evm_address_height,priority:2"`
+	ConsensusAddress string    `gorm:"not null;column:consensus_address;index:idx_cl_validator_set_snapshot_height_consensus_address,priority:2,unique"` // Upper case hex
+	EVMAddress       string    `gorm:"not null;column:evm_address;index:idx_cl_validator_set_snapshot_evm_address_height,priority:1"`                    // To lower case
+	VotingPower      int64     `gorm:"not null;column:voting_power"`
+	ProposerPriority int64     `gorm:"not null;column:proposer_priority"` // As of the snapshot height, it changes every block
+	BlockTime        time.Time `gorm:"not null;column:block_time;index:idx_cl_validator_set_snapshot_block_time"`
+}
+
+func (CLValidatorSetSnapshot) TableName() string {
+	return "cl_validator_set_snapshots"
+}
+
+func createCLValidatorSetSnapshots(tx *gorm.DB, snapshots []*CLValidatorSetSnapshot) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "height"}, {Name: "consensus_address"}},
+		UpdateAll: true,
+	}).CreateInBatches(snapshots, 100).Error
+}
+
+// GetCLValidatorSet returns the validator set in effect at the height, i.e. the latest snapshot taken at or
+// before it, by voting power. No validator is returned if the height is before the first snapshot.
+func GetCLValidatorSet(db *gorm.DB, height int64) ([]*CLValidatorSetSnapshot, error) {
+	var snapshots []*CLValidatorSetSnapshot
+	if err := db.Where("height = (?)", db.Model(&CLValidatorSetSnapshot{}).Select("MAX(height)").Where("height <= ?", height)).
+		Order("voting_power DESC, consensus_address ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// GetLatestCLValidatorSet returns the validator set of the latest snapshot.
+func GetLatestCLValidatorSet(db *gorm.DB) ([]*CLValidatorSetSnapshot, error) {
+	var snapshots []*CLValidatorSetSnapshot
+	if err := db.Where("height = (?)", db.Model(&CLValidatorSetSnapshot{}).Select("MAX(height)")).
+		Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// CLVotingPowerHist is the voting power of a validator and the total voting power of the set at a snapshot.
+type CLVotingPowerHist struct {
+	Height           int64
+	BlockTime        time.Time
+	VotingPower      int64 // 0 if the validator is not in the set
+	TotalVotingPower int64
+}
+
+// GetCLVotingPowerHists returns the voting power of the validator at each snapshot in effect since the given
+// time, starting with the one in effect at that time, in height order.
+func GetCLVotingPowerHists(db *gorm.DB, evmAddr string, since time.Time) ([]*CLVotingPowerHist, error) {
+	var startHeight int64
+	if err := db.Model(&CLValidatorSetSnapshot{}).
+		Select("COALESCE(MAX(height), 0)").
+		Where("block_time <= ?", since).
+		Scan(&startHeight).Error; err != nil {
+		return nil, err
+	}
+
+	var hists []*CLVotingPowerHist
+	if err := db.Model(&CLValidatorSetSnapshot{}).
+		Select("height, block_time, SUM(CASE WHEN evm_address = ? THEN voting_power ELSE 0 END) AS voting_power, SUM(voting_power) AS total_voting_power", evmAddr).
+		Where("height >= ?", startHeight).
+		Group("height, block_time").
+		Order("height ASC").
+		Scan(&hists).Error; err != nil {
+		return nil, err
+	}
+
+	return hists, nil
+}
@@ -0,0 +1,95 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestCLValidatorSet(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidator{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorVote{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorSetSnapshot{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorUptimeDaily{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorIncident{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	indexerName := "cl_validator_vote"
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     indexerName,
+		BlockHeight: 0,
+	}))
+
+	t1 := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Hour)
+
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+		Snapshots: []*db.CLValidatorSetSnapshot{
+			{Height: 10, ConsensusAddress: "AA", EVMAddress: "0xevm1", VotingPower: 30, ProposerPriority: -5, BlockTime: t1},
+			{Height: 10, ConsensusAddress: "BB", EVMAddress: "0xevm2", VotingPower: 10, ProposerPriority: 5, BlockTime: t1},
+		},
+	}, 10))
+
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+		Snapshots: []*db.CLValidatorSetSnapshot{
+			{Height: 20, ConsensusAddress: "BB", EVMAddress: "0xevm2", VotingPower: 40, ProposerPriority: 0, BlockTime: t2},
+		},
+	}, 20))
+
+	t.Run("GetCLValidatorSet", func(t *testing.T) {
+		set, err := db.GetCLValidatorSet(dbOperator, 5)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(set))
+
+		// The set of the latest snapshot at or before the height, by voting power.
+		set, err = db.GetCLValidatorSet(dbOperator, 15)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(set))
+		require.Equal(t, "0xevm1", set[0].EVMAddress)
+		require.Equal(t, int64(10), set[1].Height)
+
+		set, err = db.GetCLValidatorSet(dbOperator, 20)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(set))
+		require.Equal(t, int64(40), set[0].VotingPower)
+
+		latest, err := db.GetLatestCLValidatorSet(dbOperator)
+		require.NoError(t, err)
+		require.Equal(t, set[0].ID, latest[0].ID)
+	})
+
+	t.Run("GetCLVotingPowerHists", func(t *testing.T) {
+		hists, err := db.GetCLVotingPowerHists(dbOperator, "0xevm1", time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, 2, len(hists))
+		require.Equal(t, int64(10), hists[0].Height)
+		require.Equal(t, int64(30), hists[0].VotingPower)
+		require.Equal(t, int64(40), hists[0].TotalVotingPower)
+		require.Equal(t, int64(0), hists[1].VotingPower)
+		require.Equal(t, int64(40), hists[1].TotalVotingPower)
+		require.True(t, t2.Equal(hists[1].BlockTime))
+
+		// The snapshot in effect at the start time is included.
+		hists, err = db.GetCLVotingPowerHists(dbOperator, "0xevm2", t1.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, 2, len(hists))
+		require.Equal(t, int64(10), hists[0].VotingPower)
+		require.Equal(t, int64(40), hists[1].VotingPower)
+
+		hists, err = db.GetCLVotingPowerHists(dbOperator, "0xevm2", t2)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(hists))
+	})
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CLValidatorStakeHist is the running total stake of a validator after the staking events of a block changed it.
+type CLValidatorStakeHist struct {
+	ID uint64 `gorm:"primarykey"`
+
+	Validator        string `gorm:"not null;column:validator;index:idx_cl_validator_stake_hist_validator_updated_at_block,priority:1,unique;index:idx_cl_validator_stake_hist_validator_updated_at_time,priority:1"` // To lower case
+	TotalStakeAmount int64  `gorm:"not null;column:total_stake_amount"`                                                                                                                                              // In gwei
+	UpdatedAtBlock   int64  `gorm:"not null;column:updated_at_block;index:idx_cl_validator_stake_hist_validator_updated_at_block,priority:2,unique"`
+	UpdatedAtTime    int64  `gorm:"not null;column:updated_at_time;index:idx_cl_validator_stake_hist_validator_updated_at_time,priority:2"`
+}
+
+func (CLValidatorStakeHist) TableName() string {
+	return "cl_validator_stake_hists"
+}
+
+// CLValidatorStakingEvent is a successful staking event along with the validators it moves stake from and to.
+type CLValidatorStakingEvent struct {
+	CLSuccessfulStakingEvent
+	SrcValidatorAddress string `gorm:"column:src_validator_address"` // Redelegate only, empty if the el event is not indexed
+	DstValidatorAddress string `gorm:"column:dst_validator_address"` // Empty if the el event is not indexed
+}
+
+func BatchUpsertCLValidatorStakeHists(db *gorm.DB, indexer string, stakes []*CLValidatorStakeHist, height int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "validator"}, {Name: "updated_at_block"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"total_stake_amount": gorm.Expr("excluded.total_stake_amount"),
+			}),
+		}).CreateInBatches(stakes, 100).Error; err != nil {
+			return err
+		}
+
+		return UpdateIndexPoint(tx, indexer, height)
+	})
+}
+
+// GetSuccessfulCLValidatorStakingEvents returns the successful staking events of the types in [from, to] in chain
+// order, with the validators of the el events they are executed for. The events of a type in a tx are matched
+// with the el events one to one by their order, the cl events being executed in the order they were emitted.
+func GetSuccessfulCLValidatorStakingEvents(db *gorm.DB, eventTypes []string, from, to int64) ([]*CLValidatorStakingEvent, error) {
+	txHashes := db.Table("cl_staking_events").
+		Select("el_tx_hash").
+		Where("block_height >= ? AND block_height <= ?", from, to)
+
+	// The failed events count in the order too, they were emitted all the same.
+	clEvents := db.Table("cl_staking_events").
+		Select("*, ROW_NUMBER() OVER (PARTITION BY el_tx_hash, event_type ORDER BY block_height, event_index) AS ordinal").
+		Where("el_tx_hash IN (?)", txHashes)
+	elEvents := db.Table("el_staking_events").
+		Select("tx_hash, event_type, src_validator_address, dst_validator_address, ROW_NUMBER() OVER (PARTITION BY tx_hash, event_type ORDER BY log_index) AS ordinal").
+		Where("tx_hash IN (?)", txHashes)
+
+	var events []*CLValidatorStakingEvent
+	if err := db.
+		Table("(?) AS e", clEvents).
+		Joins("JOIN cl_blocks AS b ON e.block_height = b.height").
+		Joins("LEFT JOIN (?) AS el ON e.el_tx_hash = el.tx_hash AND e.event_type = el.event_type AND e.ordinal = el.ordinal", elEvents).
+		Select("e.id, e.el_tx_hash, e.event_type, e.block_height, e.event_index, e.status_ok, e.error_code, e.amount, b.time AS block_time, COALESCE(el.src_validator_address, '') AS src_validator_address, COALESCE(el.dst_validator_address, '') AS dst_validator_address").
+		Where("e.event_type IN (?)", eventTypes).
+		Where("e.status_ok = ?", true).
+		Where("e.block_height >= ? AND e.block_height <= ?", from, to).
+		Order("e.block_height ASC, e.event_index ASC").
+		Scan(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetLatestCLValidatorStakeHists returns the latest total stake of each of the validators keyed by validator,
+// validators without stake history are omitted.
+func GetLatestCLValidatorStakeHists(db *gorm.DB, validators ...string) (map[string]*CLValidatorStakeHist, error) {
+	var stakes []*CLValidatorStakeHist
+	if err := db.Where("validator IN ?", validators).
+		Where("updated_at_block = (SELECT MAX(h.updated_at_block) FROM cl_validator_stake_hists AS h WHERE h.validator = cl_validator_stake_hists.validator)").
+		Find(&stakes).Error; err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*CLValidatorStakeHist, len(stakes))
+	for _, stake := range stakes {
+		res[stake.Validator] = stake
+	}
+
+	return res, nil
+}
+
+func GetLatestCLValidatorStakeHistBefore(db *gorm.DB, validator string, timestamp int64) (*CLValidatorStakeHist, error) {
+	var stake CLValidatorStakeHist
+	if err := db.Where("validator = ? AND updated_at_time <= ?", validator, timestamp).Order("updated_at_block DESC").First(&stake).Error; err != nil {
+		return nil, err
+	}
+
+	return &stake, nil
+}
+
+// GetCLValidatorStakeHistsAfter returns the total stakes of the validator updated after the given unix timestamp
+// in time order.
+func GetCLValidatorStakeHistsAfter(db *gorm.DB, validator string, timestamp int64) ([]*CLValidatorStakeHist, error) {
+	var stakes []*CLValidatorStakeHist
+	if err := db.Where("validator = ? AND updated_at_time > ?", validator, timestamp).Order("updated_at_block ASC").Find(&stakes).Error; err != nil {
+		return nil, err
+	}
+
+	return stakes, nil
+}
@@ -0,0 +1,116 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+func TestCLValidatorStakeHist(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLBlock{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorPenalty{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorStakeHist{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	for _, name := range []string{"cl_block", "cl_staking_event", "el_staking_event", "cl_validator_stake_hist"} {
+		require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{Indexer: name}))
+	}
+
+	blocks := make([]*db.CLBlock, 0)
+	for i := int64(1); i <= 10; i++ {
+		blocks = append(blocks, &db.CLBlock{Height: i, Hash: fmt.Sprintf("hash%d", i), Time: time.Unix(i*100, 0).UTC()})
+	}
+	require.NoError(t, db.BatchCreateCLBlocks(dbOperator, "cl_block", blocks, 10))
+
+	require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, "el_staking_event", []*db.ELStakingEvent{
+		{TxHash: "tx_hash1", BlockHeight: 2, EventType: indexer.TypeStakeOnBehalf, Address: "0xop1", DstValidatorAddress: "0xval1"},
+		{TxHash: "tx_hash2", BlockHeight: 4, EventType: indexer.TypeRedelegate, Address: "0xdel1", SrcValidatorAddress: "0xval1", DstValidatorAddress: "0xval2"},
+	}, 4))
+
+	require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, "cl_staking_event", []*db.CLStakingEvent{
+		{ELTxHash: "tx_hash1", EventType: indexer.TypeStakeOnBehalf, BlockHeight: 3, StatusOK: true, Amount: "1024"},
+		{ELTxHash: "tx_hash2", EventType: indexer.TypeRedelegate, BlockHeight: 5, StatusOK: true, Amount: "512"},
+		{ELTxHash: "tx_hash3", EventType: indexer.TypeStake, BlockHeight: 5, EventIndex: 1, StatusOK: true, Amount: "2048"},
+		{ELTxHash: "tx_hash4", EventType: indexer.TypeUnstake, BlockHeight: 6, StatusOK: false, Amount: "1"},
+	}, nil, 6))
+
+	t.Run("GetSuccessfulCLValidatorStakingEvents", func(t *testing.T) {
+		events, err := db.GetSuccessfulCLValidatorStakingEvents(dbOperator, []string{indexer.TypeStake, indexer.TypeStakeOnBehalf, indexer.TypeRedelegate, indexer.TypeUnstake}, 1, 10)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(events))
+		require.Equal(t, "0xval1", events[0].DstValidatorAddress)
+		require.Equal(t, int64(300), events[0].BlockTime.Unix())
+		require.Equal(t, "0xval1", events[1].SrcValidatorAddress)
+		require.Equal(t, "0xval2", events[1].DstValidatorAddress)
+
+		// The el event of the stake is not indexed.
+		require.Equal(t, "", events[2].DstValidatorAddress)
+	})
+
+	t.Run("GetSuccessfulCLValidatorStakingEvents several events of a type in a tx", func(t *testing.T) {
+		require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, "el_staking_event", []*db.ELStakingEvent{
+			{TxHash: "tx_hash5", BlockHeight: 7, LogIndex: 1, EventType: indexer.TypeStake, Address: "0xdel1", DstValidatorAddress: "0xval1"},
+			{TxHash: "tx_hash5", BlockHeight: 7, LogIndex: 4, EventType: indexer.TypeStake, Address: "0xdel1", DstValidatorAddress: "0xval2"},
+			{TxHash: "tx_hash5", BlockHeight: 7, LogIndex: 6, EventType: indexer.TypeStake, Address: "0xdel1", DstValidatorAddress: "0xval3"},
+		}, 7))
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, "cl_staking_event", []*db.CLStakingEvent{
+			{ELTxHash: "tx_hash5", EventType: indexer.TypeStake, BlockHeight: 8, EventIndex: 0, StatusOK: true, Amount: "1"},
+			{ELTxHash: "tx_hash5", EventType: indexer.TypeStake, BlockHeight: 8, EventIndex: 1, StatusOK: false, Amount: "2"},
+			{ELTxHash: "tx_hash5", EventType: indexer.TypeStake, BlockHeight: 8, EventIndex: 2, StatusOK: true, Amount: "3"},
+		}, nil, 8))
+
+		// Each cl event is matched with the el event at its position, the failed one included.
+		events, err := db.GetSuccessfulCLValidatorStakingEvents(dbOperator, []string{indexer.TypeStake}, 8, 8)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(events))
+		require.Equal(t, "1", events[0].Amount)
+		require.Equal(t, "0xval1", events[0].DstValidatorAddress)
+		require.Equal(t, "3", events[1].Amount)
+		require.Equal(t, "0xval3", events[1].DstValidatorAddress)
+	})
+
+	require.NoError(t, db.BatchUpsertCLValidatorStakeHists(dbOperator, "cl_validator_stake_hist", []*db.CLValidatorStakeHist{
+		{Validator: "0xval1", TotalStakeAmount: 1024, UpdatedAtBlock: 3, UpdatedAtTime: 300},
+		{Validator: "0xval1", TotalStakeAmount: 512, UpdatedAtBlock: 5, UpdatedAtTime: 500},
+		{Validator: "0xval2", TotalStakeAmount: 512, UpdatedAtBlock: 5, UpdatedAtTime: 500},
+	}, 5))
+
+	t.Run("GetLatestCLValidatorStakeHists", func(t *testing.T) {
+		hists, err := db.GetLatestCLValidatorStakeHists(dbOperator, "0xval1", "0xval2", "0xval3")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(hists))
+		require.Equal(t, int64(5), hists["0xval1"].UpdatedAtBlock)
+		require.Equal(t, int64(512), hists["0xval1"].TotalStakeAmount)
+		require.Equal(t, int64(512), hists["0xval2"].TotalStakeAmount)
+	})
+
+	t.Run("GetCLValidatorStakeHists", func(t *testing.T) {
+		hist, err := db.GetLatestCLValidatorStakeHistBefore(dbOperator, "0xval1", 400)
+		require.NoError(t, err)
+		require.Equal(t, int64(1024), hist.TotalStakeAmount)
+
+		_, err = db.GetLatestCLValidatorStakeHistBefore(dbOperator, "0xval2", 400)
+		require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		hists, err := db.GetCLValidatorStakeHistsAfter(dbOperator, "0xval1", 300)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(hists))
+		require.Equal(t, int64(500), hists[0].UpdatedAtTime)
+	})
+}
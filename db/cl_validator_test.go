@@ -0,0 +1,71 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestCLValidator(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidator{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorVote{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	indexerName := "cl_validator_vote"
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     indexerName,
+		BlockHeight: 0,
+	}))
+
+	votes := []*db.CLValidatorVote{
+		{Validator: "0xevm1", BlockHeight: 1},
+		{Validator: "0xevm2", BlockHeight: 1},
+	}
+	validators := []*db.CLValidator{
+		{ConsensusAddress: "CONS1", EVMAddress: "0xevm1", VotingPower: 30, LastActiveHeight: 1},
+		{ConsensusAddress: "CONS2", EVMAddress: "0xevm2", VotingPower: 10, LastActiveHeight: 1},
+	}
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{Votes: votes, Validators: validators}, 1))
+
+	height, total, err := db.GetCLActiveSet(dbOperator)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), height)
+	require.Equal(t, int64(40), total)
+
+	// The second validator leaves the active set.
+	votes = []*db.CLValidatorVote{
+		{Validator: "0xevm1", BlockHeight: 2},
+	}
+	validators = []*db.CLValidator{
+		{ConsensusAddress: "CONS1", EVMAddress: "0xevm1", VotingPower: 50, LastActiveHeight: 2},
+	}
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{Votes: votes, Validators: validators}, 2))
+
+	height, total, err = db.GetCLActiveSet(dbOperator)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), height)
+	require.Equal(t, int64(50), total)
+
+	validator, err := db.GetCLValidatorByEVMAddress(dbOperator, "0xevm1")
+	require.NoError(t, err)
+	require.Equal(t, "CONS1", validator.ConsensusAddress)
+	require.Equal(t, int64(50), validator.VotingPower)
+	require.Equal(t, int64(2), validator.LastActiveHeight)
+
+	validatorsMap, err := db.GetCLValidatorsByEVMAddress(dbOperator, "0xevm1", "0xevm2", "0xevm3")
+	require.NoError(t, err)
+	require.Len(t, validatorsMap, 2)
+	require.Equal(t, "CONS2", validatorsMap["0xevm2"].ConsensusAddress)
+	require.Equal(t, int64(1), validatorsMap["0xevm2"].LastActiveHeight)
+}
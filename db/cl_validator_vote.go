@@ -2,8 +2,6 @@ package db
 
 import (
 	"gorm.io/gorm"
-
-	"github.com/piplabs/story-staking-api/pkg/util"
 )
 
 type CLValidatorVote struct {
@@ -16,16 +14,46 @@ func (CLValidatorVote) TableName() string {
 	return "cl_validator_votes"
 }
 
-func BatchUpdateCLValidatorVotes(db *gorm.DB, indexer string, validatorVotes []*CLValidatorVote, height int64) error {
+// CLValidatorVoteBatch is what the validator vote indexer derives from the commits of a range of blocks.
+type CLValidatorVoteBatch struct {
+	Votes      []*CLValidatorVote
+	Validators []*CLValidator            // Active validators as of the last block they are active in
+	Uptimes    []*ValidatorUptimeDaily   // Added to the stored rollups of the same day
+	Incidents  []*ValidatorIncident      // Long enough to be incidents, started or updated in the range
+	Snapshots  []*CLValidatorSetSnapshot // Taken at the blocks the set or the voting powers changed at
+
+	UptimeWindow int64 // Number of the latest blocks whose votes are kept, all are kept if not positive
+}
+
+// BatchUpdateCLValidatorVotes stores the batch, the votes out of the uptime window are pruned.
+func BatchUpdateCLValidatorVotes(db *gorm.DB, indexer string, batch *CLValidatorVoteBatch, height int64) error {
 	return db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.CreateInBatches(validatorVotes, 100).Error; err != nil {
+		if err := tx.CreateInBatches(batch.Votes, 100).Error; err != nil {
 			return err
 		}
 
-		if err := tx.Where("block_height < ?", height-util.UptimeWindow+1).Delete(&CLValidatorVote{}).Error; err != nil {
+		if err := upsertCLValidators(tx, batch.Validators); err != nil {
 			return err
 		}
 
+		if err := createCLValidatorSetSnapshots(tx, batch.Snapshots); err != nil {
+			return err
+		}
+
+		if err := addValidatorUptimes(tx, batch.Uptimes); err != nil {
+			return err
+		}
+
+		if err := upsertValidatorIncidents(tx, batch.Incidents); err != nil {
+			return err
+		}
+
+		if batch.UptimeWindow > 0 {
+			if err := tx.Where("block_height < ?", height-batch.UptimeWindow+1).Delete(&CLValidatorVote{}).Error; err != nil {
+				return err
+			}
+		}
+
 		return UpdateIndexPoint(tx, indexer, height)
 	})
 }
@@ -51,3 +79,27 @@ func GetCLValidatorsVotes(db *gorm.DB, validators ...string) (map[string]int64,
 
 	return validatorVoteCounts, nil
 }
+
+// GetLastCLValidatorVotes returns the height of the last block before the given one signed by each validator, the
+// validators without a stored vote are left out.
+func GetLastCLValidatorVotes(db *gorm.DB, validators []string, before int64) (map[string]int64, error) {
+	var results []struct {
+		Validator   string
+		BlockHeight int64
+	}
+
+	if err := db.Table("cl_validator_votes").
+		Select("validator, MAX(block_height) as block_height").
+		Where("validator IN ? AND block_height < ?", validators, before).
+		Group("validator").
+		Find(&results).Error; err != nil {
+		return nil, err
+	}
+
+	lastVotes := make(map[string]int64, len(results))
+	for _, res := range results {
+		lastVotes[res.Validator] = res.BlockHeight
+	}
+
+	return lastVotes, nil
+}
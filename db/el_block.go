@@ -1,18 +1,20 @@
 package db
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
 )
 
 type ELBlock struct {
-	ID       uint64    `gorm:"primarykey"`
-	Height   int64     `gorm:"not null;column:height;index:idx_el_block_height,unique"`
-	Hash     string    `gorm:"not null;column:hash;index:idx_el_block_hash,unique"`
-	GasUsed  uint64    `gorm:"not null"`
-	GasLimit uint64    `gorm:"not null"`
-	Time     time.Time `gorm:"not null;column:time"`
+	ID         uint64    `gorm:"primarykey"`
+	Height     int64     `gorm:"not null;column:height;index:idx_el_block_height,unique"`
+	Hash       string    `gorm:"not null;column:hash;index:idx_el_block_hash,unique"`
+	ParentHash string    `gorm:"not null;default:'';column:parent_hash"`
+	GasUsed    uint64    `gorm:"not null"`
+	GasLimit   uint64    `gorm:"not null"`
+	Time       time.Time `gorm:"not null;column:time"`
 }
 
 func (ELBlock) TableName() string {
@@ -37,3 +39,47 @@ func GetLatestELBlock(db *gorm.DB, n int) ([]*ELBlock, error) {
 
 	return elBlks, nil
 }
+
+// GetELBlock returns the block stored at the given height, or nil if the height has not been indexed.
+func GetELBlock(db *gorm.DB, height int64) (*ELBlock, error) {
+	var elBlk ELBlock
+	if err := db.Where("height = ?", height).First(&elBlk).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &elBlk, nil
+}
+
+// GetELBlocks returns the stored blocks keyed by height for blocks in [from, to].
+func GetELBlocks(db *gorm.DB, from, to int64) (map[int64]*ELBlock, error) {
+	var elBlks []*ELBlock
+	if err := db.Where("height >= ? AND height <= ?", from, to).Find(&elBlks).Error; err != nil {
+		return nil, err
+	}
+
+	blocks := make(map[int64]*ELBlock, len(elBlks))
+	for _, blk := range elBlks {
+		blocks[blk.Height] = blk
+	}
+
+	return blocks, nil
+}
+
+// GetELBlockHashes returns the stored block hashes keyed by height for blocks in [from, to].
+func GetELBlockHashes(db *gorm.DB, from, to int64) (map[int64]string, error) {
+	var elBlks []*ELBlock
+	if err := db.Select("height", "hash").
+		Where("height >= ? AND height <= ?", from, to).
+		Find(&elBlks).Error; err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[int64]string, len(elBlks))
+	for _, blk := range elBlks {
+		hashes[blk.Height] = blk.Hash
+	}
+
+	return hashes, nil
+}
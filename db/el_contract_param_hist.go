@@ -0,0 +1,62 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ELContractParamHist is a change of a parameter of the staking contract.
+type ELContractParamHist struct {
+	ID          uint64    `gorm:"primarykey"`
+	Param       string    `gorm:"not null;column:param;index:idx_el_contract_param_hist_param_block_height,priority:1;index:idx_el_contract_param_hist_tx_hash_log_index_param,priority:3,unique"`
+	Value       string    `gorm:"not null;column:value"`                                                                              // Amounts in wei, rates in basis points, addresses in lower case
+	TxHash      string    `gorm:"not null;column:tx_hash;index:idx_el_contract_param_hist_tx_hash_log_index_param,priority:1,unique"` // Empty for the initial values
+	LogIndex    uint      `gorm:"not null;column:log_index;index:idx_el_contract_param_hist_tx_hash_log_index_param,priority:2,unique"`
+	BlockHeight int64     `gorm:"not null;column:block_height;index:idx_el_contract_param_hist_param_block_height,priority:2"`
+	BlockHash   string    `gorm:"not null;column:block_hash"`
+	BlockTime   time.Time `gorm:"not null;column:block_time"`
+}
+
+func (ELContractParamHist) TableName() string {
+	return "el_contract_param_hists"
+}
+
+func BatchCreateELContractParamHists(db *gorm.DB, indexer string, hists []*ELContractParamHist, height int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}, {Name: "param"}},
+			UpdateAll: true,
+		}).CreateInBatches(hists, 100).Error; err != nil {
+			return err
+		}
+
+		return UpdateIndexPoint(tx, indexer, height)
+	})
+}
+
+func HasELContractParamHists(db *gorm.DB) (bool, error) {
+	var count int64
+	if err := db.Model(&ELContractParamHist{}).Limit(1).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetELContractParamHists returns the changes of the given parameter, or of all parameters if empty,
+// in chain order.
+func GetELContractParamHists(db *gorm.DB, param string) ([]*ELContractParamHist, error) {
+	query := db.Model(&ELContractParamHist{})
+	if param != "" {
+		query = query.Where("param = ?", param)
+	}
+
+	var hists []*ELContractParamHist
+	if err := query.Order("block_height ASC, log_index ASC").Find(&hists).Error; err != nil {
+		return nil, err
+	}
+
+	return hists, nil
+}
@@ -0,0 +1,114 @@
+package db
+
+import (
+	"gorm.io/gorm"
+)
+
+// RollbackELBlocks reverts all execution layer data derived from blocks above forkHeight and
+// rewinds the index points of the given indexers to forkHeight. It returns the addresses whose
+// cumulative rewards or withdrawals were changed, so that callers can invalidate their caches.
+func RollbackELBlocks(db *gorm.DB, forkHeight int64, indexers []string) ([]string, error) {
+	var rewardAddrs, withdrawalAddrs []string
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&ELRewardDelta{}).
+			Distinct("address").
+			Where("block_height > ?", forkHeight).
+			Pluck("address", &rewardAddrs).Error; err != nil {
+			return err
+		}
+
+		if len(rewardAddrs) > 0 {
+			if err := tx.Exec(`
+				UPDATE el_rewards SET
+					amount = amount - COALESCE((
+						SELECT SUM(d.amount) FROM el_reward_deltas AS d
+						WHERE d.address = el_rewards.address AND d.block_height > ?
+					), 0),
+					last_update_height = COALESCE((
+						SELECT MAX(d.block_height) FROM el_reward_deltas AS d
+						WHERE d.address = el_rewards.address AND d.block_height <= ?
+					), CASE WHEN last_update_height > ? THEN ? ELSE last_update_height END)
+				WHERE address IN (?)`,
+				forkHeight, forkHeight, forkHeight, forkHeight, rewardAddrs,
+			).Error; err != nil {
+				return err
+			}
+
+			// Addresses that were first rewarded after the fork point no longer have any rewards.
+			if err := tx.Where("address IN (?) AND amount = 0", rewardAddrs).Delete(&ELReward{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("block_height > ?", forkHeight).Delete(&ELRewardDelta{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&ELWithdrawalDelta{}).
+			Distinct("address").
+			Where("block_height > ?", forkHeight).
+			Pluck("address", &withdrawalAddrs).Error; err != nil {
+			return err
+		}
+
+		if len(withdrawalAddrs) > 0 {
+			if err := tx.Exec(`
+				UPDATE el_withdrawals SET
+					amount = amount - COALESCE((
+						SELECT SUM(d.amount) FROM el_withdrawal_deltas AS d
+						WHERE d.address = el_withdrawals.address AND d.kind = el_withdrawals.kind AND d.block_height > ?
+					), 0),
+					last_update_height = COALESCE((
+						SELECT MAX(d.block_height) FROM el_withdrawal_deltas AS d
+						WHERE d.address = el_withdrawals.address AND d.kind = el_withdrawals.kind AND d.block_height <= ?
+					), CASE WHEN last_update_height > ? THEN ? ELSE last_update_height END)
+				WHERE address IN (?)`,
+				forkHeight, forkHeight, forkHeight, forkHeight, withdrawalAddrs,
+			).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Where("address IN (?) AND amount = 0", withdrawalAddrs).Delete(&ELWithdrawal{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("block_height > ?", forkHeight).Delete(&ELWithdrawalDelta{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("block_height > ?", forkHeight).Delete(&ELStakingEvent{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("block_height > ?", forkHeight).Delete(&ELContractParamHist{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("block_height > ?", forkHeight).Delete(&Validator{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("block_height > ?", forkHeight).Delete(&ValidatorCommissionHist{}).Error; err != nil {
+			return err
+		}
+
+		if err := refreshValidatorCommissionRates(tx); err != nil {
+			return err
+		}
+
+		if err := tx.Where("height > ?", forkHeight).Delete(&ELBlock{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&IndexPoint{}).
+			Where("indexer IN (?) AND block_height > ?", indexers, forkHeight).
+			Update("block_height", forkHeight).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(rewardAddrs, withdrawalAddrs...), nil
+}
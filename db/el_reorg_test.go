@@ -0,0 +1,99 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestRollbackELBlocks(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELBlock{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELReward{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELRewardDelta{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELWithdrawal{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELWithdrawalDelta{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELContractParamHist{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.Validator{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorCommissionHist{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	indexers := []string{"el_block", "el_reward", "el_staking_event"}
+	for _, indexer := range indexers {
+		require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+			Indexer:     indexer,
+			BlockHeight: 0,
+		}))
+	}
+
+	blocks := []*db.ELBlock{
+		{Height: 1, Hash: "hash1", ParentHash: "hash0", Time: time.Unix(100, 0)},
+		{Height: 2, Hash: "hash2", ParentHash: "hash1", Time: time.Unix(200, 0)},
+		{Height: 3, Hash: "hash3", ParentHash: "hash2", Time: time.Unix(300, 0)},
+	}
+	require.NoError(t, db.BatchCreateELBlocks(dbOperator, "el_block", blocks, 3))
+
+	require.NoError(t, dbOperator.Create([]*db.ELReward{
+		{Address: "address1", Amount: "300", LastUpdateHeight: 3},
+		{Address: "address2", Amount: "50", LastUpdateHeight: 3},
+	}).Error)
+	require.NoError(t, dbOperator.Create([]*db.ELRewardDelta{
+		{Address: "address1", BlockHeight: 1, Amount: "100"},
+		{Address: "address1", BlockHeight: 3, Amount: "200"},
+		{Address: "address2", BlockHeight: 3, Amount: "50"},
+	}).Error)
+	require.NoError(t, db.UpdateIndexPoint(dbOperator, "el_reward", 3))
+
+	require.NoError(t, dbOperator.Create([]*db.ELWithdrawal{
+		{Address: "address3", Kind: "unstake", Amount: "1024", LastUpdateHeight: 3},
+	}).Error)
+	require.NoError(t, dbOperator.Create([]*db.ELWithdrawalDelta{
+		{Address: "address3", Kind: "unstake", BlockHeight: 3, Amount: "1024", BlockTime: 300},
+	}).Error)
+
+	require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, "el_staking_event", []*db.ELStakingEvent{
+		{TxHash: "tx_hash1", BlockHeight: 1, BlockHash: "hash1", EventType: "Stake", Address: "address1"},
+		{TxHash: "tx_hash3", BlockHeight: 3, BlockHash: "hash3", EventType: "Stake", Address: "address1"},
+	}, 2))
+
+	rewardAddrs, err := db.RollbackELBlocks(dbOperator, 1, indexers)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"address1", "address2", "address3"}, rewardAddrs)
+
+	latest, err := db.GetLatestELBlock(dbOperator, 1)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), latest[0].Height)
+
+	reward, err := db.GetELRewards(dbOperator, "address1")
+	require.NoError(t, err)
+	require.Equal(t, "100", reward.Amount)
+	require.Equal(t, int64(1), reward.LastUpdateHeight)
+
+	_, err = db.GetELRewards(dbOperator, "address2")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	_, err = db.GetELWithdrawal(dbOperator, "address3", "unstake")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	var eventCount int64
+	require.NoError(t, dbOperator.Model(&db.ELStakingEvent{}).Count(&eventCount).Error)
+	require.Equal(t, int64(1), eventCount)
+
+	for _, indexer := range indexers {
+		indexPoint, err := db.GetIndexPoint(dbOperator, indexer)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), indexPoint.BlockHeight)
+	}
+}
@@ -1,10 +1,16 @@
 package db
 
 import (
+	"errors"
+
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// ErrRewardDeltasNotCovered is returned when the cumulative rewards of a range cannot be rebuilt, as they were
+// indexed before reward deltas were recorded.
+var ErrRewardDeltasNotCovered = errors.New("range starts below the first block covered by reward deltas")
+
 type ELReward struct {
 	ID               uint64 `gorm:"primarykey"`
 	Address          string `gorm:"not null;column:address;index:idx_el_reward_address,unique"` // To lower case
@@ -16,8 +22,49 @@ func (ELReward) TableName() string {
 	return "el_rewards"
 }
 
-func BatchUpsertELRewards(db *gorm.DB, indexer string, rewards []*ELReward, height int64) error {
+// ELRewardDelta is the reward withdrawn to an address in a single block. The cumulative
+// amounts in `el_rewards` are the sum of these rows, which allows them to be reverted on reorg.
+type ELRewardDelta struct {
+	ID          uint64 `gorm:"primarykey"`
+	Address     string `gorm:"not null;column:address;index:idx_el_reward_delta_address_block_height,priority:1,unique;index:idx_el_reward_delta_address_block_time,priority:1"` // To lower case
+	BlockHeight int64  `gorm:"not null;column:block_height;index:idx_el_reward_delta_address_block_height,priority:2,unique;index:idx_el_reward_delta_block_height"`
+	Amount      string `gorm:"not null;column:amount;type:numeric"`
+	BlockTime   int64  `gorm:"not null;default:0;column:block_time;index:idx_el_reward_delta_address_block_time,priority:2"` // Unix timestamp, 0 if unknown
+}
+
+func (ELRewardDelta) TableName() string {
+	return "el_reward_deltas"
+}
+
+// ELRewardDeltaStart is the first block height covered by the reward deltas, recorded when they are introduced on
+// a deployment that already indexed rewards. There is no row if deltas cover all the indexed blocks.
+type ELRewardDeltaStart struct {
+	ID          uint64 `gorm:"primarykey"`
+	BlockHeight int64  `gorm:"not null;column:block_height"`
+}
+
+func (ELRewardDeltaStart) TableName() string {
+	return "el_reward_delta_starts"
+}
+
+// GetELRewardDeltaStart returns the first block height covered by the reward deltas, 0 if they cover all blocks.
+func GetELRewardDeltaStart(db *gorm.DB) (int64, error) {
+	var starts []*ELRewardDeltaStart
+	if err := db.Limit(1).Find(&starts).Error; err != nil {
+		return 0, err
+	} else if len(starts) == 0 {
+		return 0, nil
+	}
+
+	return starts[0].BlockHeight, nil
+}
+
+func BatchUpsertELRewards(db *gorm.DB, indexer string, rewards []*ELReward, deltas []*ELRewardDelta, height int64) error {
 	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(deltas, 100).Error; err != nil {
+			return err
+		}
+
 		if err := tx.Clauses(clause.OnConflict{
 			Columns: []clause.Column{{Name: "address"}},
 			DoUpdates: clause.Assignments(map[string]interface{}{
@@ -40,3 +87,25 @@ func GetELRewards(db *gorm.DB, evmAddr string) (*ELReward, error) {
 
 	return &reward, nil
 }
+
+// AmountBucket is the sum of the amounts withdrawn to an address in a time bucket.
+type AmountBucket struct {
+	BucketTime int64  `gorm:"column:bucket_time"` // Unix timestamp of the start of the bucket
+	Amount     string `gorm:"column:amount;type:numeric"`
+}
+
+// GetELRewardBuckets sums the rewards withdrawn to the address since the given unix timestamp into buckets
+// of bucketSeconds aligned to the unix epoch, in time order. Buckets without rewards are omitted.
+func GetELRewardBuckets(db *gorm.DB, evmAddr string, since, bucketSeconds int64) ([]*AmountBucket, error) {
+	var buckets []*AmountBucket
+	if err := db.Model(&ELRewardDelta{}).
+		Select("(block_time / ?) * ? AS bucket_time, SUM(amount) AS amount", bucketSeconds, bucketSeconds).
+		Where("address = ? AND block_time >= ? AND block_time > 0", evmAddr, since).
+		Group("bucket_time").
+		Order("bucket_time ASC").
+		Scan(&buckets).Error; err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
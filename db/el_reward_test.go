@@ -0,0 +1,53 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestGetELRewardBuckets(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELRewardDelta{}))
+
+	require.NoError(t, dbOperator.Create([]*db.ELRewardDelta{
+		{Address: "address1", BlockHeight: 1, Amount: "10", BlockTime: 3600},
+		{Address: "address1", BlockHeight: 2, Amount: "20", BlockTime: 3601},
+		{Address: "address1", BlockHeight: 3, Amount: "30", BlockTime: 7300},
+		{Address: "address1", BlockHeight: 4, Amount: "40", BlockTime: 90000},
+		{Address: "address1", BlockHeight: 5, Amount: "50", BlockTime: 0},
+		{Address: "address2", BlockHeight: 1, Amount: "60", BlockTime: 3600},
+	}).Error)
+
+	t.Run("hourly", func(t *testing.T) {
+		buckets, err := db.GetELRewardBuckets(dbOperator, "address1", 0, 3600)
+		require.NoError(t, err)
+		require.Len(t, buckets, 3)
+		require.Equal(t, int64(3600), buckets[0].BucketTime)
+		require.Equal(t, "30", buckets[0].Amount)
+		require.Equal(t, int64(7200), buckets[1].BucketTime)
+		require.Equal(t, "30", buckets[1].Amount)
+		require.Equal(t, int64(90000), buckets[2].BucketTime)
+		require.Equal(t, "40", buckets[2].Amount)
+	})
+
+	t.Run("daily since", func(t *testing.T) {
+		buckets, err := db.GetELRewardBuckets(dbOperator, "address1", 3601, 86400)
+		require.NoError(t, err)
+		require.Len(t, buckets, 2)
+		require.Equal(t, int64(0), buckets[0].BucketTime)
+		require.Equal(t, "50", buckets[0].Amount)
+		require.Equal(t, int64(86400), buckets[1].BucketTime)
+		require.Equal(t, "40", buckets[1].Amount)
+	})
+}
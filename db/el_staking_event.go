@@ -1,16 +1,24 @@
 package db
 
-import "gorm.io/gorm"
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
 
 type ELStakingEvent struct {
 	ID                  uint64 `gorm:"primarykey"`
-	TxHash              string `gorm:"not null;column:tx_hash;index:idx_el_staking_event_tx_hash_event_type,priority:1"`
-	EventType           string `gorm:"not null;column:event_type;index:idx_el_staking_event_tx_hash_event_type,priority:2"`
+	TxHash              string `gorm:"not null;column:tx_hash;index:idx_el_staking_event_tx_hash_event_type,priority:1;index:idx_el_staking_event_tx_hash_log_index_event_type,priority:1,unique"`
+	EventType           string `gorm:"not null;column:event_type;index:idx_el_staking_event_tx_hash_event_type,priority:2;index:idx_el_staking_event_tx_hash_log_index_event_type,priority:3,unique"`
 	Address             string `gorm:"not null;column:address;index:idx_el_staking_event_address_block_height,priority:1"` // To lower case
 	BlockHeight         int64  `gorm:"not null;column:block_height;index:idx_el_staking_event_address_block_height,priority:2"`
+	BlockHash           string `gorm:"not null;default:'';column:block_hash"`
+	LogIndex            uint   `gorm:"not null;default:0;column:log_index;index:idx_el_staking_event_tx_hash_log_index_event_type,priority:2,unique"`
 	SrcValidatorAddress string `gorm:"not null;column:src_validator_address"`
 	DstValidatorAddress string `gorm:"not null;column:dst_validator_address"`
-	DstAddress          string `gorm:"not null;column:dst_address"` // RewardAddrss | WithdrawAddress | OperatorAddress
+	DstAddress          string `gorm:"not null;column:dst_address"`                           // RewardAddrss | WithdrawAddress | OperatorAddress | Delegator (*OnBehalf)
+	StakeAmount         string `gorm:"not null;default:0;column:stake_amount;type:numeric"`   // In wei, requested by Deposit | Withdraw | Redelegate
+	StakingPeriod       string `gorm:"not null;default:0;column:staking_period;type:numeric"` // Deposit
+	DelegationID        string `gorm:"not null;default:0;column:delegation_id;type:numeric"`  // Deposit | Withdraw | Redelegate
 }
 
 func (ELStakingEvent) TableName() string {
@@ -19,10 +27,42 @@ func (ELStakingEvent) TableName() string {
 
 func BatchCreateELStakingEvents(db *gorm.DB, indexer string, events []*ELStakingEvent, height int64) error {
 	return db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.CreateInBatches(events, 100).Error; err != nil {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}, {Name: "event_type"}},
+			UpdateAll: true,
+		}).CreateInBatches(events, 100).Error; err != nil {
 			return err
 		}
 
 		return UpdateIndexPoint(tx, indexer, height)
 	})
 }
+
+// ELStakingEventStatus is the outcome of an el staking event on the consensus layer.
+type ELStakingEventStatus struct {
+	TxHash   string `gorm:"column:tx_hash"`
+	LogIndex uint   `gorm:"column:log_index"`
+	StatusOK bool   `gorm:"column:status_ok"`
+}
+
+// GetELStakingEventStatuses returns the outcome of the el staking events of the txs already executed by the consensus
+// layer. The events of a type in a tx are matched with the cl events one to one by their order, the cl events being
+// executed in the order they were emitted.
+func GetELStakingEventStatuses(db *gorm.DB, txHashes []string) ([]*ELStakingEventStatus, error) {
+	elEvents := db.Table("el_staking_events").
+		Select("tx_hash, log_index, event_type, ROW_NUMBER() OVER (PARTITION BY tx_hash, event_type ORDER BY log_index) AS ordinal").
+		Where("tx_hash IN ?", txHashes)
+	clEvents := db.Table("cl_staking_events").
+		Select("el_tx_hash, event_type, status_ok, ROW_NUMBER() OVER (PARTITION BY el_tx_hash, event_type ORDER BY block_height, event_index) AS ordinal").
+		Where("el_tx_hash IN ?", txHashes)
+
+	var statuses []*ELStakingEventStatus
+	if err := db.Table("(?) AS el", elEvents).
+		Joins("INNER JOIN (?) AS cl ON el.tx_hash = cl.el_tx_hash AND el.event_type = cl.event_type AND el.ordinal = cl.ordinal", clEvents).
+		Select("el.tx_hash AS tx_hash, el.log_index AS log_index, cl.status_ok AS status_ok").
+		Scan(&statuses).Error; err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
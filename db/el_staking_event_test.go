@@ -0,0 +1,52 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+func TestGetELStakingEventStatuses(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorPenalty{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	for _, name := range []string{"cl_staking_event", "el_staking_event"} {
+		require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{Indexer: name}))
+	}
+
+	// A rejected validator creation retried in the same tx, and a commission update not executed yet.
+	require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, "el_staking_event", []*db.ELStakingEvent{
+		{TxHash: "tx_hash1", BlockHeight: 1, LogIndex: 2, EventType: indexer.TypeCreateValidator, Address: "0xval1"},
+		{TxHash: "tx_hash1", BlockHeight: 1, LogIndex: 5, EventType: indexer.TypeCreateValidator, Address: "0xval1"},
+		{TxHash: "tx_hash2", BlockHeight: 2, LogIndex: 0, EventType: indexer.TypeUpdateValidatorCommission, Address: "0xval1"},
+	}, 2))
+	require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, "cl_staking_event", []*db.CLStakingEvent{
+		{ELTxHash: "tx_hash1", EventType: indexer.TypeCreateValidator, BlockHeight: 2, EventIndex: 0, StatusOK: false, ErrorCode: "Unspecified"},
+		{ELTxHash: "tx_hash1", EventType: indexer.TypeCreateValidator, BlockHeight: 2, EventIndex: 1, StatusOK: true},
+	}, nil, 2))
+
+	statuses, err := db.GetELStakingEventStatuses(dbOperator, []string{"tx_hash1", "tx_hash2"})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(statuses))
+
+	statusOK := make(map[uint]bool)
+	for _, status := range statuses {
+		require.Equal(t, "tx_hash1", status.TxHash)
+		statusOK[status.LogIndex] = status.StatusOK
+	}
+	require.Equal(t, map[uint]bool{2: false, 5: true}, statusOK)
+}
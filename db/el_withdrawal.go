@@ -0,0 +1,90 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ELWithdrawal is the cumulative amount of a kind of non-reward withdrawal to an address, each kind
+// being a separate ledger.
+type ELWithdrawal struct {
+	ID               uint64 `gorm:"primarykey"`
+	Address          string `gorm:"not null;column:address;index:idx_el_withdrawal_address_kind,priority:1,unique"` // To lower case
+	Kind             string `gorm:"not null;column:kind;index:idx_el_withdrawal_address_kind,priority:2,unique"`    // unstake | ubi
+	Amount           string `gorm:"not null;column:amount;type:numeric"`
+	LastUpdateHeight int64  `gorm:"not null;column:last_update_height"`
+}
+
+func (ELWithdrawal) TableName() string {
+	return "el_withdrawals"
+}
+
+// ELWithdrawalDelta is the amount of a kind of withdrawal to an address in a single block, the
+// cumulative amounts in `el_withdrawals` are the sum of these rows.
+type ELWithdrawalDelta struct {
+	ID          uint64 `gorm:"primarykey"`
+	Address     string `gorm:"not null;column:address;index:idx_el_withdrawal_delta_address_kind_block_height,priority:1,unique;index:idx_el_withdrawal_delta_address_kind_block_time,priority:1"` // To lower case
+	Kind        string `gorm:"not null;column:kind;index:idx_el_withdrawal_delta_address_kind_block_height,priority:2,unique;index:idx_el_withdrawal_delta_address_kind_block_time,priority:2"`
+	BlockHeight int64  `gorm:"not null;column:block_height;index:idx_el_withdrawal_delta_address_kind_block_height,priority:3,unique;index:idx_el_withdrawal_delta_block_height"`
+	Amount      string `gorm:"not null;column:amount;type:numeric"`
+	BlockTime   int64  `gorm:"not null;column:block_time;index:idx_el_withdrawal_delta_address_kind_block_time,priority:3"` // Unix timestamp
+}
+
+func (ELWithdrawalDelta) TableName() string {
+	return "el_withdrawal_deltas"
+}
+
+func BatchUpsertELWithdrawals(db *gorm.DB, indexer string, withdrawals []*ELWithdrawal, deltas []*ELWithdrawalDelta, height int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(deltas, 100).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "address"}, {Name: "kind"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"amount":             gorm.Expr("el_withdrawals.amount + excluded.amount"),
+				"last_update_height": gorm.Expr("GREATEST(el_withdrawals.last_update_height, excluded.last_update_height)"),
+			}),
+		}).CreateInBatches(withdrawals, 100).Error; err != nil {
+			return err
+		}
+
+		return UpdateIndexPoint(tx, indexer, height)
+	})
+}
+
+func GetELWithdrawal(db *gorm.DB, evmAddr, kind string) (*ELWithdrawal, error) {
+	var withdrawal ELWithdrawal
+	if err := db.Where("address = ? AND kind = ?", evmAddr, kind).First(&withdrawal).Error; err != nil {
+		return nil, err
+	}
+
+	return &withdrawal, nil
+}
+
+// GetELWithdrawalBuckets sums the withdrawals of the kind to the address since the given unix timestamp
+// into buckets of bucketSeconds aligned to the unix epoch, in time order. Buckets without withdrawals are omitted.
+func GetELWithdrawalBuckets(db *gorm.DB, evmAddr, kind string, since, bucketSeconds int64) ([]*AmountBucket, error) {
+	var buckets []*AmountBucket
+	if err := db.Model(&ELWithdrawalDelta{}).
+		Select("(block_time / ?) * ? AS bucket_time, SUM(amount) AS amount", bucketSeconds, bucketSeconds).
+		Where("address = ? AND kind = ? AND block_time >= ?", evmAddr, kind, since).
+		Group("bucket_time").
+		Order("bucket_time ASC").
+		Scan(&buckets).Error; err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// GetELWithdrawalDeltas returns the per-block withdrawals of the kind to any of the addresses in block order.
+func GetELWithdrawalDeltas(db *gorm.DB, evmAddrs []string, kind string) ([]*ELWithdrawalDelta, error) {
+	var deltas []*ELWithdrawalDelta
+	if err := db.Where("address IN (?) AND kind = ?", evmAddrs, kind).Order("block_height ASC, id ASC").Find(&deltas).Error; err != nil {
+		return nil, err
+	}
+
+	return deltas, nil
+}
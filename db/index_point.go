@@ -11,6 +11,12 @@ type IndexPoint struct {
 	ID          uint64 `gorm:"primarykey"`
 	Indexer     string `gorm:"not null;column:indexer;index:idx_index_point_indexer,unique"`
 	BlockHeight int64  `gorm:"not null;column:block_height"`
+
+	// Status of the indexer, reported by the writer.
+	ChainHead     int64      `gorm:"not null;default:0;column:chain_head"`
+	LastError     string     `gorm:"not null;default:'';column:last_error"`
+	LastErrorAt   *time.Time `gorm:"column:last_error_at"`
+	LastSuccessAt *time.Time `gorm:"column:last_success_at"`
 }
 
 func (IndexPoint) TableName() string {
@@ -33,6 +39,15 @@ func GetIndexPoint(db *gorm.DB, indexer string) (*IndexPoint, error) {
 	return &indexPoint, nil
 }
 
+func GetIndexPoints(db *gorm.DB) ([]*IndexPoint, error) {
+	var indexPoints []*IndexPoint
+	if err := db.Order("indexer ASC").Find(&indexPoints).Error; err != nil {
+		return nil, err
+	}
+
+	return indexPoints, nil
+}
+
 func GetIndexPointTime(db *gorm.DB, indexer string) (time.Time, error) {
 	var blockTime time.Time
 
@@ -47,6 +62,23 @@ func GetIndexPointTime(db *gorm.DB, indexer string) (time.Time, error) {
 	return blockTime, nil
 }
 
+// UpdateIndexPoint moves the index point to the block height, the last error is cleared as the indexer recovered.
 func UpdateIndexPoint(db *gorm.DB, indexer string, blockHeight int64) error {
-	return db.Model(&IndexPoint{}).Where("indexer = ?", indexer).Update("block_height", blockHeight).Error
+	return db.Model(&IndexPoint{}).Where("indexer = ?", indexer).Updates(map[string]interface{}{
+		"block_height":    blockHeight,
+		"last_success_at": time.Now(),
+		"last_error":      "",
+		"last_error_at":   nil,
+	}).Error
+}
+
+func UpdateIndexPointChainHead(db *gorm.DB, indexer string, chainHead int64) error {
+	return db.Model(&IndexPoint{}).Where("indexer = ?", indexer).Update("chain_head", chainHead).Error
+}
+
+func UpdateIndexPointError(db *gorm.DB, indexer string, errMsg string) error {
+	return db.Model(&IndexPoint{}).Where("indexer = ?", indexer).Updates(map[string]interface{}{
+		"last_error":    errMsg,
+		"last_error_at": time.Now(),
+	}).Error
 }
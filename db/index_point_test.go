@@ -0,0 +1,40 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestIndexPoint(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{Indexer: "cl_block"}))
+
+	require.NoError(t, db.UpdateIndexPointError(dbOperator, "cl_block", "rpc unavailable"))
+
+	indexPoint, err := db.GetIndexPoint(dbOperator, "cl_block")
+	require.NoError(t, err)
+	require.Equal(t, "rpc unavailable", indexPoint.LastError)
+	require.NotNil(t, indexPoint.LastErrorAt)
+
+	// The error is cleared once the indexer recovers.
+	require.NoError(t, db.UpdateIndexPoint(dbOperator, "cl_block", 10))
+
+	indexPoint, err = db.GetIndexPoint(dbOperator, "cl_block")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), indexPoint.BlockHeight)
+	require.Equal(t, "", indexPoint.LastError)
+	require.Nil(t, indexPoint.LastErrorAt)
+	require.NotNil(t, indexPoint.LastSuccessAt)
+}
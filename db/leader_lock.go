@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// LeaderLockKey is the Postgres advisory lock key held by the active writer.
+const LeaderLockKey int64 = 0x5354414b494e47 // "STAKING"
+
+// LeaderLock is a session level Postgres advisory lock used to elect a single active writer. It is held
+// on a dedicated connection, Postgres releases it as soon as the session of the holder ends.
+type LeaderLock struct {
+	db   *sql.DB
+	key  int64
+	conn *sql.Conn
+}
+
+func NewLeaderLock(db *gorm.DB, key int64) (*LeaderLock, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LeaderLock{
+		db:  sqlDB,
+		key: key,
+	}, nil
+}
+
+// TryAcquire tries to take the lock without blocking and reports whether it is held.
+func (l *LeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	if l.conn == nil {
+		conn, err := l.db.Conn(ctx)
+		if err != nil {
+			return false, err
+		}
+		l.conn = conn
+	}
+
+	var acquired bool
+	if err := l.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		l.close()
+		return false, err
+	}
+
+	return acquired, nil
+}
+
+// Check verifies that the session holding the lock is still alive, the lock is lost otherwise.
+func (l *LeaderLock) Check(ctx context.Context) error {
+	if l.conn == nil {
+		return errors.New("leader lock not held")
+	}
+
+	if err := l.conn.PingContext(ctx); err != nil {
+		l.close()
+		return err
+	}
+
+	return nil
+}
+
+// Release releases the lock and its connection.
+func (l *LeaderLock) Release(ctx context.Context) error {
+	if l.conn == nil {
+		return nil
+	}
+	defer l.close()
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+
+	return err
+}
+
+func (l *LeaderLock) close() {
+	// Discard the connection instead of returning it to the pool, ending the session along with its locks.
+	_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = l.conn.Close()
+	l.conn = nil
+}
@@ -0,0 +1,311 @@
+package db
+
+import (
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MigrateStakingEventKeys prepares the staking event tables for their unique keys, it must run before they are
+// auto migrated. Rows inserted before the keys existed may hold the duplicates of retried batches, the rows
+// identical to an earlier one are deleted in place, and the event and log indexes they lack are numbered in the
+// order the rows were inserted, which is chain order. Identical events of the same transaction cannot be told
+// apart from duplicates, only one of them is kept. The stake histories built from the duplicates are rewound to
+// the first block they inflated, so that their indexers build them again. It is a no-op once the keys exist.
+func MigrateStakingEventKeys(db *gorm.DB, totalStakeIndexer, validatorStakeIndexer string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The first cl block executing a duplicate, found before they are deleted.
+		var rewindHeights []int64
+
+		if needsStakingEventKey(tx, &CLStakingEvent{}, "idx_cl_staking_event_block_height_event_index") {
+			dups, err := duplicateStakingEvents(tx, &CLStakingEvent{})
+			if err != nil {
+				return err
+			}
+
+			var height sql.NullInt64
+			if err := tx.Table("cl_staking_events").Select("MIN(block_height)").Where("id IN (?)", dups).Scan(&height).Error; err != nil {
+				return err
+			} else if height.Valid {
+				rewindHeights = append(rewindHeights, height.Int64)
+			}
+
+			if err := migrateStakingEventKey(tx, &CLStakingEvent{}, dups, "EventIndex", "event_index", "block_height"); err != nil {
+				return err
+			}
+		}
+
+		if needsStakingEventKey(tx, &ELStakingEvent{}, "idx_el_staking_event_tx_hash_log_index_event_type") {
+			dups, err := duplicateStakingEvents(tx, &ELStakingEvent{})
+			if err != nil {
+				return err
+			}
+
+			if tx.Migrator().HasTable(&CLStakingEvent{}) {
+				var height sql.NullInt64
+				if err := tx.Table("cl_staking_events").
+					Select("MIN(block_height)").
+					Where("el_tx_hash IN (?)", tx.Table("el_staking_events").Select("tx_hash").Where("id IN (?)", dups)).
+					Scan(&height).Error; err != nil {
+					return err
+				} else if height.Valid {
+					rewindHeights = append(rewindHeights, height.Int64)
+				}
+			}
+
+			if err := migrateStakingEventKey(tx, &ELStakingEvent{}, dups, "LogIndex", "log_index", "tx_hash"); err != nil {
+				return err
+			}
+		}
+
+		if len(rewindHeights) == 0 {
+			return nil
+		}
+
+		return rewindStakeHists(tx, slices.Min(rewindHeights), totalStakeIndexer, validatorStakeIndexer)
+	})
+}
+
+func needsStakingEventKey(tx *gorm.DB, model interface{}, keyIndex string) bool {
+	return tx.Migrator().HasTable(model) && !tx.Migrator().HasIndex(model, keyIndex)
+}
+
+// duplicateStakingEvents returns the query of the ids of the rows identical in all their stored columns to a row
+// inserted before.
+func duplicateStakingEvents(tx *gorm.DB, model interface{ TableName() string }) (*gorm.DB, error) {
+	columnTypes, err := tx.Migrator().ColumnTypes(model)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]string, 0, len(columnTypes))
+	for _, c := range columnTypes {
+		if c.Name() != "id" {
+			columns = append(columns, c.Name())
+		}
+	}
+
+	firsts := tx.Table(model.TableName()).Select("MIN(id)").Group(strings.Join(columns, ", "))
+
+	return tx.Table(model.TableName()).Select("id").Where("id NOT IN (?)", firsts), nil
+}
+
+// migrateStakingEventKey deletes the duplicates of the table, then adds the index column of its key if missing and
+// numbers the rows of each partition in insertion order.
+func migrateStakingEventKey(tx *gorm.DB, model interface{ TableName() string }, dups *gorm.DB, field, column, partition string) error {
+	if err := tx.Where("id IN (?)", dups).Delete(model).Error; err != nil {
+		return err
+	}
+
+	if tx.Migrator().HasColumn(model, column) {
+		return nil
+	}
+
+	if err := tx.Migrator().AddColumn(model, field); err != nil {
+		return err
+	}
+
+	table := model.TableName()
+
+	return tx.Exec(`
+		UPDATE ` + table + ` SET ` + column + ` = r.ordinal
+		FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY ` + partition + ` ORDER BY id) - 1 AS ordinal FROM ` + table + `) AS r
+		WHERE r.id = ` + table + `.id`,
+	).Error
+}
+
+// rewindStakeHists deletes the stake histories from the height on and rewinds the index points of their indexers
+// before it.
+func rewindStakeHists(tx *gorm.DB, height int64, totalStakeIndexer, validatorStakeIndexer string) error {
+	for _, model := range []interface{}{&CLTotalStakeHist{}, &CLValidatorStakeHist{}} {
+		if tx.Migrator().HasTable(model) {
+			if err := tx.Where("updated_at_block >= ?", height).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	if tx.Migrator().HasTable(&CLTotalStakeDrift{}) {
+		if err := tx.Where("block_height >= ?", height).Delete(&CLTotalStakeDrift{}).Error; err != nil {
+			return err
+		}
+	}
+
+	if !tx.Migrator().HasTable(&IndexPoint{}) {
+		return nil
+	}
+
+	return tx.Model(&IndexPoint{}).
+		Where("indexer IN ? AND block_height >= ?", []string{totalStakeIndexer, validatorStakeIndexer}, height).
+		Update("block_height", height-1).Error
+}
+
+// MigrateELRewardDeltaBlockTime adds the block time to the reward deltas indexed before it was stored,
+// it must run before they are auto migrated. Times are copied from the indexed execution layer blocks,
+// deltas of blocks that were not indexed keep 0 until their range is reindexed.
+func MigrateELRewardDeltaBlockTime(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&ELRewardDelta{}) || db.Migrator().HasColumn(&ELRewardDelta{}, "block_time") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&ELRewardDelta{}, "BlockTime"); err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE el_reward_deltas SET block_time = CAST(EXTRACT(EPOCH FROM b.time) AS BIGINT)
+			FROM el_blocks AS b
+			WHERE b.height = el_reward_deltas.block_height`,
+		).Error
+	})
+}
+
+// MigrateELRewardDeltaStart records the first block height covered by the reward deltas when they are introduced
+// on a deployment that already indexed rewards, it must run before they are auto migrated. The cumulative rewards
+// of the blocks indexed before have no deltas and cannot be rebuilt from them.
+func MigrateELRewardDeltaStart(db *gorm.DB, indexer string) error {
+	if db.Migrator().HasTable(&ELRewardDelta{}) || !db.Migrator().HasTable(&ELReward{}) || !db.Migrator().HasTable(&IndexPoint{}) {
+		return nil
+	}
+
+	var indexPoints []*IndexPoint
+	if err := db.Where("indexer = ?", indexer).Limit(1).Find(&indexPoints).Error; err != nil {
+		return err
+	} else if len(indexPoints) == 0 || indexPoints[0].BlockHeight == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&ELRewardDeltaStart{}); err != nil {
+			return err
+		}
+
+		return tx.Create(&ELRewardDeltaStart{BlockHeight: indexPoints[0].BlockHeight + 1}).Error
+	})
+}
+
+// MigrateValidatorUptimeDaily builds the daily uptime rollups from the stored votes when they are introduced on a
+// deployment that already indexed votes, it must run before they are auto migrated. Only the votes of the uptime
+// window are left, the days before are not backfilled. Votes record the signed blocks only, a validator is taken
+// to be in the active set from its first to its last stored vote, and the blocks not indexed by the cl block
+// indexer are left out as their day is unknown.
+func MigrateValidatorUptimeDaily(db *gorm.DB, indexer string) error {
+	if db.Migrator().HasTable(&ValidatorUptimeDaily{}) || !db.Migrator().HasTable(&CLValidatorVote{}) ||
+		!db.Migrator().HasTable(&CLBlock{}) || !db.Migrator().HasTable(&IndexPoint{}) {
+		return nil
+	}
+
+	var indexPoints []*IndexPoint
+	if err := db.Where("indexer = ?", indexer).Limit(1).Find(&indexPoints).Error; err != nil {
+		return err
+	} else if len(indexPoints) == 0 || indexPoints[0].BlockHeight == 0 {
+		return nil
+	}
+	// The indexer resumes after the index point, the votes after it are left to it.
+	to := indexPoints[0].BlockHeight
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&ValidatorUptimeDaily{}); err != nil {
+			return err
+		}
+
+		var spans []struct {
+			Validator string
+			First     int64
+			Last      int64
+		}
+		if err := tx.Model(&CLValidatorVote{}).
+			Select("validator, MIN(block_height) AS first, MAX(block_height) AS last").
+			Where("block_height <= ?", to).
+			Group("validator").
+			Find(&spans).Error; err != nil {
+			return err
+		} else if len(spans) == 0 {
+			return nil
+		}
+
+		from := spans[0].First
+		for _, span := range spans {
+			from = min(from, span.First)
+		}
+
+		var blocks []*CLBlock
+		if err := tx.Select("height, time").
+			Where("height BETWEEN ? AND ?", from, to).
+			Order("height ASC").
+			Find(&blocks).Error; err != nil {
+			return err
+		}
+
+		// The heights of the indexed blocks of each day, in height order as the days are.
+		type dayBlocks struct {
+			day     int64
+			heights []int64
+		}
+		days := make([]*dayBlocks, 0)
+		for _, blk := range blocks {
+			day := blk.Time.UTC().Truncate(24 * time.Hour).Unix()
+			if n := len(days); n == 0 || days[n-1].day != day {
+				days = append(days, &dayBlocks{day: day})
+			}
+			days[len(days)-1].heights = append(days[len(days)-1].heights, blk.Height)
+		}
+
+		for _, d := range days {
+			var signed []struct {
+				Validator string
+				Count     int64
+			}
+			if err := tx.Model(&CLValidatorVote{}).
+				Select("validator, COUNT(*) AS count").
+				Where("block_height IN (?)", tx.Model(&CLBlock{}).
+					Select("height").
+					Where("height BETWEEN ? AND ?", d.heights[0], d.heights[len(d.heights)-1])).
+				Group("validator").
+				Find(&signed).Error; err != nil {
+				return err
+			}
+
+			signedBlocks := make(map[string]int64, len(signed))
+			for _, s := range signed {
+				signedBlocks[s.Validator] = s.Count
+			}
+
+			uptimes := make([]*ValidatorUptimeDaily, 0)
+			for _, span := range spans {
+				lo, _ := slices.BinarySearch(d.heights, span.First)
+				hi, found := slices.BinarySearch(d.heights, span.Last)
+				if found {
+					hi++
+				}
+
+				total := int64(hi - lo)
+				if total <= 0 {
+					continue
+				}
+
+				uptimes = append(uptimes, &ValidatorUptimeDaily{
+					Validator:    span.Validator,
+					Day:          d.day,
+					SignedBlocks: signedBlocks[span.Validator],
+					MissedBlocks: total - signedBlocks[span.Validator],
+					TotalBlocks:  total,
+				})
+			}
+
+			if len(uptimes) == 0 {
+				continue
+			}
+
+			if err := tx.CreateInBatches(uptimes, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,179 @@
+package db_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestMigrateStakingEventKeys(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	// Tables as created before the unique keys existed.
+	require.NoError(t, dbOperator.Exec(`CREATE TABLE cl_staking_events (
+		id integer PRIMARY KEY AUTOINCREMENT, el_tx_hash text NOT NULL, event_type text NOT NULL,
+		block_height integer NOT NULL, status_ok numeric NOT NULL, error_code text NOT NULL, amount text NOT NULL)`).Error)
+	require.NoError(t, dbOperator.Exec(`CREATE TABLE el_staking_events (
+		id integer PRIMARY KEY AUTOINCREMENT, tx_hash text NOT NULL, event_type text NOT NULL, address text NOT NULL,
+		block_height integer NOT NULL, block_hash text NOT NULL DEFAULT '', src_validator_address text NOT NULL,
+		dst_validator_address text NOT NULL, dst_address text NOT NULL)`).Error)
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+	for _, indexer := range []string{"cl_staking_event", "el_staking_event", "cl_total_stake_hist", "cl_validator_stake_hist"} {
+		require.NoError(t, dbOperator.Create(&db.IndexPoint{Indexer: indexer, BlockHeight: 10}).Error)
+	}
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLTotalStakeHist{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorStakeHist{}))
+	for _, height := range []int64{1, 5, 8} {
+		require.NoError(t, dbOperator.Create(&db.CLTotalStakeHist{TotalStakeAmount: height, UpdatedAtBlock: height, UpdatedAtTime: height}).Error)
+		require.NoError(t, dbOperator.Create(&db.CLValidatorStakeHist{Validator: "validator1", TotalStakeAmount: height, UpdatedAtBlock: height, UpdatedAtTime: height}).Error)
+	}
+
+	// A stake of a retried batch at cl height 5, the el event of its tx is duplicated too.
+	require.NoError(t, dbOperator.Exec(`INSERT INTO cl_staking_events (el_tx_hash, event_type, block_height, status_ok, error_code, amount) VALUES
+		('tx_hash1', 'Stake', 2, true, '', '100'),
+		('tx_hash2', 'Stake', 5, true, '', '100'),
+		('tx_hash3', 'Unstake', 5, true, '', '50'),
+		('tx_hash2', 'Stake', 5, true, '', '100')`).Error)
+	require.NoError(t, dbOperator.Exec(`INSERT INTO el_staking_events (tx_hash, event_type, address, block_height, src_validator_address, dst_validator_address, dst_address) VALUES
+		('tx_hash1', 'Stake', 'address1', 1, '', 'validator1', ''),
+		('tx_hash2', 'Stake', 'address1', 4, '', 'validator1', ''),
+		('tx_hash2', 'SetOperator', 'address1', 4, '', '', 'operator1'),
+		('tx_hash2', 'Stake', 'address1', 4, '', 'validator1', '')`).Error)
+
+	require.NoError(t, db.MigrateStakingEventKeys(dbOperator, "cl_total_stake_hist", "cl_validator_stake_hist"))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELStakingEvent{}))
+
+	// The duplicates are deleted in place, the rows left are numbered in insertion order.
+	var clEvents []*db.CLStakingEvent
+	require.NoError(t, dbOperator.Order("id ASC").Find(&clEvents).Error)
+	require.Equal(t, 3, len(clEvents))
+	require.Equal(t, 0, clEvents[0].EventIndex)
+	require.Equal(t, "tx_hash2", clEvents[1].ELTxHash)
+	require.Equal(t, 0, clEvents[1].EventIndex)
+	require.Equal(t, "tx_hash3", clEvents[2].ELTxHash)
+	require.Equal(t, 1, clEvents[2].EventIndex)
+
+	var elEvents []*db.ELStakingEvent
+	require.NoError(t, dbOperator.Order("id ASC").Find(&elEvents).Error)
+	require.Equal(t, 3, len(elEvents))
+	require.Equal(t, uint(0), elEvents[1].LogIndex)
+	require.Equal(t, "SetOperator", elEvents[2].EventType)
+	require.Equal(t, uint(1), elEvents[2].LogIndex)
+
+	// The staking event indexers keep their index points, the stake histories are rebuilt from the first duplicate.
+	for indexer, height := range map[string]int64{"cl_staking_event": 10, "el_staking_event": 10, "cl_total_stake_hist": 4, "cl_validator_stake_hist": 4} {
+		point, err := db.GetIndexPoint(dbOperator, indexer)
+		require.NoError(t, err)
+		require.Equal(t, height, point.BlockHeight, indexer)
+	}
+
+	var count int64
+	require.NoError(t, dbOperator.Model(&db.CLTotalStakeHist{}).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+	require.NoError(t, dbOperator.Model(&db.CLValidatorStakeHist{}).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+
+	// New events are kept apart by their real indexes.
+	require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, "el_staking_event", []*db.ELStakingEvent{
+		{TxHash: "tx_hash4", EventType: "Stake", Address: "address1", BlockHeight: 11, LogIndex: 3, DstValidatorAddress: "validator1"},
+		{TxHash: "tx_hash4", EventType: "Stake", Address: "address1", BlockHeight: 11, LogIndex: 5, DstValidatorAddress: "validator1"},
+	}, 11))
+	require.NoError(t, dbOperator.Model(&db.ELStakingEvent{}).Count(&count).Error)
+	require.Equal(t, int64(5), count)
+
+	// Running it again once the keys exist is a no-op.
+	require.NoError(t, db.MigrateStakingEventKeys(dbOperator, "cl_total_stake_hist", "cl_validator_stake_hist"))
+	require.NoError(t, dbOperator.Model(&db.ELStakingEvent{}).Count(&count).Error)
+	require.Equal(t, int64(5), count)
+	point, err := db.GetIndexPoint(dbOperator, "cl_total_stake_hist")
+	require.NoError(t, err)
+	require.Equal(t, int64(4), point.BlockHeight)
+}
+
+func TestMigrateValidatorUptimeDaily(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	// Votes indexed up to block 6 before the uptime rollups existed.
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLBlock{}, &db.CLValidatorVote{}, &db.IndexPoint{}))
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     "cl_validator_vote",
+		BlockHeight: 6,
+	}))
+
+	day1 := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	// Block 5 was not indexed.
+	for i, blkTime := range map[int64]time.Time{
+		1: day1.Add(time.Hour),
+		2: day1.Add(2 * time.Hour),
+		3: day1.Add(23 * time.Hour),
+		4: day2.Add(time.Hour),
+		6: day2.Add(3 * time.Hour),
+	} {
+		require.NoError(t, dbOperator.Create(&db.CLBlock{Height: i, Hash: fmt.Sprintf("hash%d", i), ProposerAddress: "proposer", Time: blkTime}).Error)
+	}
+
+	votes := []*db.CLValidatorVote{
+		// Missed block 3.
+		{Validator: "validator1", BlockHeight: 1},
+		{Validator: "validator1", BlockHeight: 2},
+		{Validator: "validator1", BlockHeight: 4},
+		{Validator: "validator1", BlockHeight: 5},
+		{Validator: "validator1", BlockHeight: 6},
+		// Active from block 3 to 4.
+		{Validator: "validator2", BlockHeight: 3},
+		{Validator: "validator2", BlockHeight: 4},
+		// After the index point.
+		{Validator: "validator3", BlockHeight: 7},
+	}
+	require.NoError(t, dbOperator.Create(votes).Error)
+
+	require.NoError(t, db.MigrateValidatorUptimeDaily(dbOperator, "cl_validator_vote"))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorUptimeDaily{}))
+
+	// Migrating again once the rollups exist is a no-op.
+	require.NoError(t, db.MigrateValidatorUptimeDaily(dbOperator, "cl_validator_vote"))
+
+	uptimes, err := db.GetValidatorUptimeDaily(dbOperator, "validator1", 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(uptimes))
+	require.Equal(t, day1.Unix(), uptimes[0].Day)
+	require.Equal(t, int64(2), uptimes[0].SignedBlocks)
+	require.Equal(t, int64(1), uptimes[0].MissedBlocks)
+	require.Equal(t, int64(3), uptimes[0].TotalBlocks)
+	require.Equal(t, day2.Unix(), uptimes[1].Day)
+	require.Equal(t, int64(2), uptimes[1].SignedBlocks)
+	require.Equal(t, int64(0), uptimes[1].MissedBlocks)
+	require.Equal(t, int64(2), uptimes[1].TotalBlocks)
+
+	uptimes, err = db.GetValidatorUptimeDaily(dbOperator, "validator2", 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(uptimes))
+	require.Equal(t, int64(1), uptimes[0].TotalBlocks)
+	require.Equal(t, int64(1), uptimes[0].SignedBlocks)
+	require.Equal(t, int64(1), uptimes[1].TotalBlocks)
+	require.Equal(t, int64(1), uptimes[1].SignedBlocks)
+
+	uptimes, err = db.GetValidatorUptimeDaily(dbOperator, "validator3", 0)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(uptimes))
+}
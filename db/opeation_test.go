@@ -2,6 +2,7 @@ package db_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
@@ -20,8 +21,12 @@ func TestOperation(t *testing.T) {
 	defer dbConn.Close()
 
 	require.NoError(t, dbOperator.AutoMigrate(&db.CLStakingEvent{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorPenalty{}))
 	require.NoError(t, dbOperator.AutoMigrate(&db.ELStakingEvent{}))
 	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLBlock{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELWithdrawal{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELWithdrawalDelta{}))
 
 	clIndexerName := "cl_staking_event"
 	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
@@ -52,13 +57,14 @@ func TestOperation(t *testing.T) {
 		clStakingEvents := []*db.CLStakingEvent{
 			{
 				ELTxHash:    "tx_hash1",
+				EventType:   indexer.TypeStake,
 				BlockHeight: 3,
 				StatusOK:    true,
 				ErrorCode:   "",
 				Amount:      "1000000000000000000",
 			},
 		}
-		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, 3))
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, nil, 3))
 
 		events, total, err := db.GetOperations(dbOperator, "address1", 1, 100)
 		require.NoError(t, err)
@@ -89,13 +95,14 @@ func TestOperation(t *testing.T) {
 		clStakingEvents := []*db.CLStakingEvent{
 			{
 				ELTxHash:    "tx_hash2",
+				EventType:   indexer.TypeStake,
 				BlockHeight: 4,
 				StatusOK:    false,
 				ErrorCode:   "Unspecified",
 				Amount:      "1000000000000000000",
 			},
 		}
-		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, 4))
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, nil, 4))
 
 		events, total, err := db.GetOperations(dbOperator, "address2", 1, 100)
 		require.NoError(t, err)
@@ -108,4 +115,268 @@ func TestOperation(t *testing.T) {
 		require.Equal(t, "Unspecified", events[0].ErrorCode)
 		require.Equal(t, "1000000000000000000", events[0].Amount)
 	})
+
+	t.Run("reindexed event", func(t *testing.T) {
+		elStakingEvents := []*db.ELStakingEvent{
+			{
+				TxHash:              "tx_hash3",
+				BlockHeight:         5,
+				LogIndex:            1,
+				EventType:           indexer.TypeStake,
+				Address:             "address3",
+				DstValidatorAddress: "dst_validator_address1",
+			},
+		}
+		require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, elIndexerName, elStakingEvents, 5))
+		require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, elIndexerName, elStakingEvents, 5))
+
+		clStakingEvents := []*db.CLStakingEvent{
+			{
+				ELTxHash:    "tx_hash3",
+				EventType:   indexer.TypeStake,
+				BlockHeight: 6,
+				StatusOK:    true,
+				Amount:      "1000000000000000000",
+			},
+		}
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, nil, 6))
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, nil, 6))
+
+		events, total, err := db.GetOperations(dbOperator, "address3", 1, 100)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), total)
+		require.Equal(t, 1, len(events))
+		require.Equal(t, "tx_hash3", events[0].TxHash)
+	})
+
+	t.Run("same type events of a tx", func(t *testing.T) {
+		elStakingEvents := []*db.ELStakingEvent{
+			{
+				TxHash:              "tx_hash10",
+				BlockHeight:         13,
+				LogIndex:            4,
+				EventType:           indexer.TypeStake,
+				Address:             "address6",
+				DstValidatorAddress: "dst_validator_address2",
+			},
+			{
+				TxHash:              "tx_hash10",
+				BlockHeight:         13,
+				LogIndex:            2,
+				EventType:           indexer.TypeStake,
+				Address:             "address6",
+				DstValidatorAddress: "dst_validator_address1",
+			},
+		}
+		require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, elIndexerName, elStakingEvents, 13))
+
+		clStakingEvents := []*db.CLStakingEvent{
+			{
+				ELTxHash:    "tx_hash10",
+				EventType:   indexer.TypeStake,
+				BlockHeight: 14,
+				StatusOK:    true,
+				Amount:      "1000",
+			},
+			{
+				ELTxHash:    "tx_hash10",
+				EventType:   indexer.TypeStake,
+				BlockHeight: 14,
+				EventIndex:  1,
+				StatusOK:    false,
+				ErrorCode:   "Unspecified",
+			},
+		}
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, nil, 14))
+
+		// Each event is matched once, with the cl event of the same rank.
+		events, total, err := db.GetOperations(dbOperator, "address6", 1, 100)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), total)
+		require.Equal(t, 2, len(events))
+		require.Equal(t, "dst_validator_address2", events[0].DstValidatorAddress)
+		require.False(t, events[0].StatusOK)
+		require.Equal(t, "dst_validator_address1", events[1].DstValidatorAddress)
+		require.True(t, events[1].StatusOK)
+		require.Equal(t, "1000", events[1].Amount)
+
+		require.NoError(t, db.BatchCreateCLBlocks(dbOperator, "cl_block", []*db.CLBlock{
+			{Height: 14, Hash: "cl_block_hash14", Time: time.Unix(1400, 0).UTC()},
+		}, 14))
+
+		stakes, err := db.GetSucceededOperations(dbOperator, "address6", indexer.TypeStake)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(stakes))
+		require.Equal(t, "dst_validator_address1", stakes[0].DstValidatorAddress)
+		require.Equal(t, "1000", stakes[0].Amount)
+	})
+
+	t.Run("requested amount", func(t *testing.T) {
+		elStakingEvents := []*db.ELStakingEvent{
+			{
+				TxHash:              "tx_hash4",
+				BlockHeight:         7,
+				EventType:           indexer.TypeStake,
+				Address:             "address4",
+				DstValidatorAddress: "dst_validator_address1",
+				StakeAmount:         "1024000000000000000",
+				StakingPeriod:       "1",
+				DelegationID:        "3",
+			},
+			{
+				TxHash:      "tx_hash5",
+				BlockHeight: 7,
+				EventType:   indexer.TypeSetOperator,
+				Address:     "address4",
+				DstAddress:  "operator1",
+			},
+		}
+		require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, elIndexerName, elStakingEvents, 7))
+
+		clStakingEvents := []*db.CLStakingEvent{
+			{
+				ELTxHash:    "tx_hash4",
+				EventType:   indexer.TypeStake,
+				BlockHeight: 8,
+				StatusOK:    true,
+			},
+			{
+				ELTxHash:    "tx_hash5",
+				EventType:   indexer.TypeSetOperator,
+				BlockHeight: 8,
+				EventIndex:  1,
+				StatusOK:    true,
+			},
+		}
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, nil, 8))
+
+		events, total, err := db.GetOperations(dbOperator, "address4", 1, 100)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), total)
+		require.Equal(t, 2, len(events))
+
+		stake := events[0]
+		if stake.TxHash != "tx_hash4" {
+			stake = events[1]
+		}
+		require.Equal(t, "1024000000000000000", stake.StakeAmount)
+		require.Equal(t, "1", stake.StakingPeriod)
+		require.Equal(t, "3", stake.DelegationID)
+	})
+
+	t.Run("succeeded unstake", func(t *testing.T) {
+		elStakingEvents := []*db.ELStakingEvent{
+			{
+				TxHash:              "tx_hash6",
+				BlockHeight:         9,
+				EventType:           indexer.TypeUnstake,
+				Address:             "address5",
+				DstValidatorAddress: "dst_validator_address1",
+				StakeAmount:         "1024000000000000000",
+			},
+			{
+				TxHash:              "tx_hash7",
+				BlockHeight:         9,
+				LogIndex:            1,
+				EventType:           indexer.TypeUnstake,
+				Address:             "address5",
+				DstValidatorAddress: "dst_validator_address1",
+			},
+			{
+				TxHash:      "tx_hash8",
+				BlockHeight: 9,
+				LogIndex:    2,
+				EventType:   indexer.TypeSetWithdrawalAddress,
+				Address:     "address5",
+				DstAddress:  "withdrawal_address1",
+			},
+			{
+				TxHash:              "tx_hash9",
+				BlockHeight:         9,
+				LogIndex:            3,
+				EventType:           indexer.TypeUnstakeOnBehalf,
+				Address:             "operator_address1",
+				DstValidatorAddress: "dst_validator_address1",
+				DstAddress:          "address5",
+			},
+		}
+		require.NoError(t, db.BatchCreateELStakingEvents(dbOperator, elIndexerName, elStakingEvents, 9))
+
+		clBlockTime := time.Unix(1000, 0).UTC()
+		require.NoError(t, db.BatchCreateCLBlocks(dbOperator, "cl_block", []*db.CLBlock{
+			{Height: 10, Hash: "cl_block_hash10", Time: clBlockTime},
+		}, 10))
+
+		clStakingEvents := []*db.CLStakingEvent{
+			{
+				ELTxHash:    "tx_hash6",
+				EventType:   indexer.TypeUnstake,
+				BlockHeight: 10,
+				StatusOK:    true,
+				Amount:      "1024000000",
+			},
+			{
+				ELTxHash:    "tx_hash7",
+				EventType:   indexer.TypeUnstake,
+				BlockHeight: 10,
+				EventIndex:  1,
+				StatusOK:    false,
+				ErrorCode:   "Unspecified",
+			},
+			{
+				ELTxHash:    "tx_hash8",
+				EventType:   indexer.TypeSetWithdrawalAddress,
+				BlockHeight: 10,
+				EventIndex:  2,
+				StatusOK:    true,
+			},
+			{
+				ELTxHash:    "tx_hash9",
+				EventType:   indexer.TypeUnstakeOnBehalf,
+				BlockHeight: 10,
+				EventIndex:  3,
+				StatusOK:    true,
+				Amount:      "512000000",
+			},
+		}
+		require.NoError(t, db.BatchCreateCLStakingEvents(dbOperator, clIndexerName, clStakingEvents, nil, 10))
+
+		unstakes, err := db.GetSucceededOperations(dbOperator, "address5", indexer.TypeUnstake)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(unstakes))
+		require.Equal(t, "tx_hash6", unstakes[0].TxHash)
+		require.Equal(t, "1024000000", unstakes[0].Amount)
+		require.Equal(t, "1024000000000000000", unstakes[0].StakeAmount)
+		require.Equal(t, int64(10), unstakes[0].CLBlockHeight)
+		require.True(t, clBlockTime.Equal(unstakes[0].CLBlockTime))
+
+		sets, err := db.GetSucceededOperations(dbOperator, "address5", indexer.TypeSetWithdrawalAddress)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(sets))
+		require.Equal(t, "withdrawal_address1", sets[0].DstAddress)
+
+		// Unstakes sent on behalf of the delegator are found by the delegator, not by the operator.
+		onBehalf, err := db.GetSucceededOperationsOnBehalf(dbOperator, "address5", indexer.TypeUnstakeOnBehalf)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(onBehalf))
+		require.Equal(t, "tx_hash9", onBehalf[0].TxHash)
+		require.Equal(t, "512000000", onBehalf[0].Amount)
+
+		onBehalf, err = db.GetSucceededOperationsOnBehalf(dbOperator, "operator_address1", indexer.TypeUnstakeOnBehalf)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(onBehalf))
+
+		require.NoError(t, db.BatchUpsertELWithdrawals(dbOperator, "el_withdrawal", nil, []*db.ELWithdrawalDelta{
+			{Address: "withdrawal_address1", Kind: indexer.WithdrawalKindUnstake, BlockHeight: 12, Amount: "24000000", BlockTime: 1200},
+			{Address: "address5", Kind: indexer.WithdrawalKindUnstake, BlockHeight: 11, Amount: "1000000000", BlockTime: 1100},
+			{Address: "address5", Kind: indexer.WithdrawalKindUBI, BlockHeight: 11, Amount: "1", BlockTime: 1100},
+		}, 12))
+
+		deltas, err := db.GetELWithdrawalDeltas(dbOperator, []string{"address5", "withdrawal_address1"}, indexer.WithdrawalKindUnstake)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(deltas))
+		require.Equal(t, int64(11), deltas[0].BlockHeight)
+		require.Equal(t, "1000000000", deltas[0].Amount)
+		require.Equal(t, int64(12), deltas[1].BlockHeight)
+	})
 }
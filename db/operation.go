@@ -1,6 +1,8 @@
 package db
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -12,6 +14,9 @@ type Operation struct {
 	SrcValidatorAddress string `gorm:"column:src_validator_address" json:"src_validator_address"`
 	DstValidatorAddress string `gorm:"column:dst_validator_address" json:"dst_validator_address"`
 	DstAddress          string `gorm:"column:dst_address" json:"dst_address"`
+	StakeAmount         string `gorm:"column:stake_amount;type:numeric" json:"stake_amount"`
+	StakingPeriod       string `gorm:"column:staking_period;type:numeric" json:"staking_period"`
+	DelegationID        string `gorm:"column:delegation_id;type:numeric" json:"delegation_id"`
 
 	StatusOK  bool   `gorm:"column:status_ok" json:"status_ok"`
 	ErrorCode string `gorm:"column:error_code" json:"error_code"`
@@ -27,9 +32,7 @@ func GetOperations(db *gorm.DB, evmAddr string, page, perPage int) ([]*Operation
 	}
 	offset := (page - 1) * perPage
 
-	query := db.Table("el_staking_events AS el").
-		Joins("INNER JOIN cl_staking_events AS cl ON el.tx_hash = cl.el_tx_hash AND el.event_type = cl.event_type").
-		Where("el.address = ?", evmAddr)
+	query := operationsQuery(db, "address", evmAddr)
 
 	// Perform the count query
 	var totalOperations int64
@@ -48,11 +51,14 @@ func GetOperations(db *gorm.DB, evmAddr string, page, perPage int) ([]*Operation
 			el.src_validator_address AS src_validator_address,
 			el.dst_validator_address AS dst_validator_address,
 			el.dst_address AS dst_address,
+			el.stake_amount AS stake_amount,
+			el.staking_period AS staking_period,
+			el.delegation_id AS delegation_id,
 			cl.status_ok AS status_ok,
 			cl.error_code AS error_code,
 			cl.amount AS amount
 		`).
-		Order("el.block_height DESC").
+		Order("el.block_height DESC, el.log_index DESC").
 		Limit(perPage).
 		Offset(offset).
 		Scan(&operations).Error; err != nil {
@@ -61,3 +67,74 @@ func GetOperations(db *gorm.DB, evmAddr string, page, perPage int) ([]*Operation
 
 	return operations, totalOperations, nil
 }
+
+// operationsQuery joins the el staking events whose column is the address with the cl events executing them. The events
+// of a type in a tx are matched with the cl events one to one by their order, the cl events being executed in the
+// order they were emitted.
+func operationsQuery(db *gorm.DB, column, addr string) *gorm.DB {
+	txHashes := db.Table("el_staking_events").
+		Select("tx_hash").
+		Where(column+" = ?", addr)
+
+	// The events of other addresses in the txs count in the order too.
+	elEvents := db.Table("el_staking_events").
+		Select("*, ROW_NUMBER() OVER (PARTITION BY tx_hash, event_type ORDER BY log_index) AS ordinal").
+		Where("tx_hash IN (?)", txHashes)
+	clEvents := db.Table("cl_staking_events").
+		Select("*, ROW_NUMBER() OVER (PARTITION BY el_tx_hash, event_type ORDER BY block_height, event_index) AS ordinal").
+		Where("el_tx_hash IN (?)", txHashes)
+
+	return db.Table("(?) AS el", elEvents).
+		Joins("INNER JOIN (?) AS cl ON el.tx_hash = cl.el_tx_hash AND el.event_type = cl.event_type AND el.ordinal = cl.ordinal", clEvents).
+		Where("el."+column+" = ?", addr)
+}
+
+// SucceededOperation is an operation executed successfully on the consensus layer, along with the
+// consensus block it was executed in.
+type SucceededOperation struct {
+	Operation
+	CLBlockHeight int64     `gorm:"column:cl_block_height"`
+	CLBlockTime   time.Time `gorm:"column:cl_block_time"`
+}
+
+// GetSucceededOperations returns the operations of the event type sent by the address that succeeded on the
+// consensus layer, in execution order.
+func GetSucceededOperations(db *gorm.DB, evmAddr, eventType string) ([]*SucceededOperation, error) {
+	return getSucceededOperations(db, "address", evmAddr, eventType)
+}
+
+// GetSucceededOperationsOnBehalf returns the operations of the event type sent on behalf of the delegator that
+// succeeded on the consensus layer, in execution order.
+func GetSucceededOperationsOnBehalf(db *gorm.DB, delAddr, eventType string) ([]*SucceededOperation, error) {
+	return getSucceededOperations(db, "dst_address", delAddr, eventType)
+}
+
+func getSucceededOperations(db *gorm.DB, column, addr, eventType string) ([]*SucceededOperation, error) {
+	var operations []*SucceededOperation
+	if err := operationsQuery(db, column, addr).
+		Joins("INNER JOIN cl_blocks AS b ON cl.block_height = b.height").
+		Select(`
+			el.tx_hash AS tx_hash,
+			el.block_height AS block_height,
+			el.event_type AS event_type,
+			el.address AS address,
+			el.src_validator_address AS src_validator_address,
+			el.dst_validator_address AS dst_validator_address,
+			el.dst_address AS dst_address,
+			el.stake_amount AS stake_amount,
+			el.staking_period AS staking_period,
+			el.delegation_id AS delegation_id,
+			cl.status_ok AS status_ok,
+			cl.error_code AS error_code,
+			cl.amount AS amount,
+			cl.block_height AS cl_block_height,
+			b.time AS cl_block_time
+		`).
+		Where("el.event_type = ? AND cl.status_ok = ?", eventType, true).
+		Order("cl.block_height ASC, cl.event_index ASC").
+		Scan(&operations).Error; err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
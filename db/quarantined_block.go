@@ -0,0 +1,47 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// QuarantinedBlock is a block an indexer failed to index too many times, it is skipped until replayed.
+type QuarantinedBlock struct {
+	ID          uint64    `gorm:"primarykey"`
+	Indexer     string    `gorm:"not null;column:indexer;index:idx_quarantined_block_indexer_block_height,priority:1,unique"`
+	BlockHeight int64     `gorm:"not null;column:block_height;index:idx_quarantined_block_indexer_block_height,priority:2,unique"`
+	Event       string    `gorm:"not null;column:event"` // The offending event, if known
+	Error       string    `gorm:"not null;column:error"`
+	Retries     int       `gorm:"not null;column:retries"`
+	CreatedAt   time.Time `gorm:"not null;column:created_at"`
+}
+
+func (QuarantinedBlock) TableName() string {
+	return "quarantined_blocks"
+}
+
+func CreateQuarantinedBlock(db *gorm.DB, block *QuarantinedBlock) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "indexer"}, {Name: "block_height"}},
+		UpdateAll: true,
+	}).Create(block).Error
+}
+
+// GetQuarantinedBlocks returns the quarantined blocks of an indexer in [from, to] ordered by height.
+func GetQuarantinedBlocks(db *gorm.DB, indexer string, from, to int64) ([]*QuarantinedBlock, error) {
+	var blocks []*QuarantinedBlock
+	if err := db.
+		Where("indexer = ? AND block_height >= ? AND block_height <= ?", indexer, from, to).
+		Order("block_height ASC").
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+func DeleteQuarantinedBlock(db *gorm.DB, indexer string, height int64) error {
+	return db.Where("indexer = ? AND block_height = ?", indexer, height).Delete(&QuarantinedBlock{}).Error
+}
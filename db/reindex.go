@@ -0,0 +1,244 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The Replace* functions delete the data of the blocks in [from, to] and insert the rebuilt data
+// in a single transaction. Index points are left untouched, the range must already be indexed.
+
+func ReplaceCLBlocks(db *gorm.DB, from, to int64, blocks []*CLBlock) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("height >= ? AND height <= ?", from, to).Delete(&CLBlock{}).Error; err != nil {
+			return err
+		}
+
+		return tx.CreateInBatches(blocks, 100).Error
+	})
+}
+
+func ReplaceCLStakingEvents(db *gorm.DB, from, to int64, events []*CLStakingEvent, penalties []*CLValidatorPenalty) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("block_height >= ? AND block_height <= ?", from, to).Delete(&CLStakingEvent{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.CreateInBatches(events, 100).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("block_height >= ? AND block_height <= ?", from, to).Delete(&CLValidatorPenalty{}).Error; err != nil {
+			return err
+		}
+
+		return tx.CreateInBatches(penalties, 100).Error
+	})
+}
+
+func ReplaceELBlocks(db *gorm.DB, from, to int64, blocks []*ELBlock) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("height >= ? AND height <= ?", from, to).Delete(&ELBlock{}).Error; err != nil {
+			return err
+		}
+
+		return tx.CreateInBatches(blocks, 100).Error
+	})
+}
+
+func ReplaceELStakingEvents(db *gorm.DB, from, to int64, events []*ELStakingEvent) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("block_height >= ? AND block_height <= ?", from, to).Delete(&ELStakingEvent{}).Error; err != nil {
+			return err
+		}
+
+		return tx.CreateInBatches(events, 100).Error
+	})
+}
+
+func ReplaceELContractParamHists(db *gorm.DB, from, to int64, hists []*ELContractParamHist) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The initial values have no transaction, they are not rebuilt.
+		if err := tx.Where("block_height >= ? AND block_height <= ? AND tx_hash <> ''", from, to).Delete(&ELContractParamHist{}).Error; err != nil {
+			return err
+		}
+
+		return tx.CreateInBatches(hists, 100).Error
+	})
+}
+
+func ReplaceValidators(db *gorm.DB, from, to int64, validators []*Validator, hists []*ValidatorCommissionHist) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("block_height >= ? AND block_height <= ?", from, to).Delete(&Validator{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("block_height >= ? AND block_height <= ?", from, to).Delete(&ValidatorCommissionHist{}).Error; err != nil {
+			return err
+		}
+
+		return createValidators(tx, validators, hists)
+	})
+}
+
+// ReplaceELRewards subtracts the reward deltas of [from, to] from the cumulative rewards and adds the
+// rebuilt ones back. It returns the addresses whose cumulative rewards may have changed. Ranges starting below
+// the first block covered by the deltas are refused with ErrRewardDeltasNotCovered, their rewards would be
+// counted twice.
+func ReplaceELRewards(db *gorm.DB, from, to int64, rewards []*ELReward, deltas []*ELRewardDelta) ([]string, error) {
+	var rewardAddrs []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		deltaStart, err := GetELRewardDeltaStart(tx)
+		if err != nil {
+			return err
+		} else if from < deltaStart {
+			return fmt.Errorf("%w: %d", ErrRewardDeltasNotCovered, deltaStart)
+		}
+
+		if err := tx.Model(&ELRewardDelta{}).
+			Distinct("address").
+			Where("block_height >= ? AND block_height <= ?", from, to).
+			Pluck("address", &rewardAddrs).Error; err != nil {
+			return err
+		}
+
+		if len(rewardAddrs) > 0 {
+			if err := tx.Exec(`
+				UPDATE el_rewards SET
+					amount = amount - COALESCE((
+						SELECT SUM(d.amount) FROM el_reward_deltas AS d
+						WHERE d.address = el_rewards.address AND d.block_height >= ? AND d.block_height <= ?
+					), 0)
+				WHERE address IN (?)`,
+				from, to, rewardAddrs,
+			).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("block_height >= ? AND block_height <= ?", from, to).Delete(&ELRewardDelta{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.CreateInBatches(deltas, 100).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "address"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"amount": gorm.Expr("el_rewards.amount + excluded.amount"),
+			}),
+		}).CreateInBatches(rewards, 100).Error; err != nil {
+			return err
+		}
+
+		for _, r := range rewards {
+			rewardAddrs = append(rewardAddrs, r.Address)
+		}
+		slices.Sort(rewardAddrs)
+		rewardAddrs = slices.Compact(rewardAddrs)
+
+		if len(rewardAddrs) == 0 {
+			return nil
+		}
+
+		// Rewards of addresses indexed before deltas were recorded keep their last update height.
+		if err := tx.Exec(`
+			UPDATE el_rewards SET
+				last_update_height = COALESCE((
+					SELECT MAX(d.block_height) FROM el_reward_deltas AS d
+					WHERE d.address = el_rewards.address
+				), last_update_height)
+			WHERE address IN (?)`,
+			rewardAddrs,
+		).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("address IN (?) AND amount = 0", rewardAddrs).Delete(&ELReward{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rewardAddrs, nil
+}
+
+// ReplaceELWithdrawals subtracts the withdrawal deltas of [from, to] from the cumulative withdrawals and adds
+// the rebuilt ones back. It returns the addresses whose cumulative withdrawals may have changed.
+func ReplaceELWithdrawals(db *gorm.DB, from, to int64, withdrawals []*ELWithdrawal, deltas []*ELWithdrawalDelta) ([]string, error) {
+	var withdrawalAddrs []string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&ELWithdrawalDelta{}).
+			Distinct("address").
+			Where("block_height >= ? AND block_height <= ?", from, to).
+			Pluck("address", &withdrawalAddrs).Error; err != nil {
+			return err
+		}
+
+		if len(withdrawalAddrs) > 0 {
+			if err := tx.Exec(`
+				UPDATE el_withdrawals SET
+					amount = amount - COALESCE((
+						SELECT SUM(d.amount) FROM el_withdrawal_deltas AS d
+						WHERE d.address = el_withdrawals.address AND d.kind = el_withdrawals.kind
+							AND d.block_height >= ? AND d.block_height <= ?
+					), 0)
+				WHERE address IN (?)`,
+				from, to, withdrawalAddrs,
+			).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("block_height >= ? AND block_height <= ?", from, to).Delete(&ELWithdrawalDelta{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.CreateInBatches(deltas, 100).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "address"}, {Name: "kind"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"amount": gorm.Expr("el_withdrawals.amount + excluded.amount"),
+			}),
+		}).CreateInBatches(withdrawals, 100).Error; err != nil {
+			return err
+		}
+
+		for _, w := range withdrawals {
+			withdrawalAddrs = append(withdrawalAddrs, w.Address)
+		}
+		slices.Sort(withdrawalAddrs)
+		withdrawalAddrs = slices.Compact(withdrawalAddrs)
+
+		if len(withdrawalAddrs) == 0 {
+			return nil
+		}
+
+		if err := tx.Exec(`
+			UPDATE el_withdrawals SET
+				last_update_height = COALESCE((
+					SELECT MAX(d.block_height) FROM el_withdrawal_deltas AS d
+					WHERE d.address = el_withdrawals.address AND d.kind = el_withdrawals.kind
+				), last_update_height)
+			WHERE address IN (?)`,
+			withdrawalAddrs,
+		).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("address IN (?) AND amount = 0", withdrawalAddrs).Delete(&ELWithdrawal{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withdrawalAddrs, nil
+}
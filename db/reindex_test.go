@@ -0,0 +1,125 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestReplaceELRewards(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELReward{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELRewardDelta{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELRewardDeltaStart{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     "el_reward",
+		BlockHeight: 3,
+	}))
+
+	require.NoError(t, dbOperator.Create([]*db.ELReward{
+		{Address: "address1", Amount: "300", LastUpdateHeight: 3},
+		{Address: "address2", Amount: "50", LastUpdateHeight: 2},
+	}).Error)
+	require.NoError(t, dbOperator.Create([]*db.ELRewardDelta{
+		{Address: "address1", BlockHeight: 1, Amount: "100"},
+		{Address: "address1", BlockHeight: 3, Amount: "200"},
+		{Address: "address2", BlockHeight: 2, Amount: "50"},
+	}).Error)
+
+	// Block 2 and 3 are rebuilt: address2 was wrongly credited, address3 was missed.
+	rewardAddrs, err := db.ReplaceELRewards(dbOperator, 2, 3, []*db.ELReward{
+		{Address: "address1", Amount: "150", LastUpdateHeight: 3},
+		{Address: "address3", Amount: "10", LastUpdateHeight: 2},
+	}, []*db.ELRewardDelta{
+		{Address: "address1", BlockHeight: 3, Amount: "150"},
+		{Address: "address3", BlockHeight: 2, Amount: "10"},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"address1", "address2", "address3"}, rewardAddrs)
+
+	reward, err := db.GetELRewards(dbOperator, "address1")
+	require.NoError(t, err)
+	require.Equal(t, "250", reward.Amount)
+	require.Equal(t, int64(3), reward.LastUpdateHeight)
+
+	_, err = db.GetELRewards(dbOperator, "address2")
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	reward, err = db.GetELRewards(dbOperator, "address3")
+	require.NoError(t, err)
+	require.Equal(t, "10", reward.Amount)
+	require.Equal(t, int64(2), reward.LastUpdateHeight)
+
+	var deltaCount int64
+	require.NoError(t, dbOperator.Model(&db.ELRewardDelta{}).Count(&deltaCount).Error)
+	require.Equal(t, int64(3), deltaCount)
+
+	indexPoint, err := db.GetIndexPoint(dbOperator, "el_reward")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), indexPoint.BlockHeight)
+}
+
+func TestReplaceELRewardsBeforeDeltas(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	// Rewards indexed up to block 10 before reward deltas were recorded.
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELReward{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     "el_reward",
+		BlockHeight: 10,
+	}))
+	require.NoError(t, dbOperator.Create(&db.ELReward{Address: "address1", Amount: "300", LastUpdateHeight: 8}).Error)
+
+	require.NoError(t, db.MigrateELRewardDeltaStart(dbOperator, "el_reward"))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELRewardDelta{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ELRewardDeltaStart{}))
+
+	// Migrating again once the deltas exist is a no-op.
+	require.NoError(t, db.MigrateELRewardDeltaStart(dbOperator, "el_reward"))
+
+	deltaStart, err := db.GetELRewardDeltaStart(dbOperator)
+	require.NoError(t, err)
+	require.Equal(t, int64(11), deltaStart)
+
+	// The totals of the range have no deltas to subtract, rebuilding them would count them twice.
+	_, err = db.ReplaceELRewards(dbOperator, 5, 12, []*db.ELReward{
+		{Address: "address1", Amount: "100", LastUpdateHeight: 8},
+	}, []*db.ELRewardDelta{
+		{Address: "address1", BlockHeight: 8, Amount: "100"},
+	})
+	require.ErrorIs(t, err, db.ErrRewardDeltasNotCovered)
+
+	reward, err := db.GetELRewards(dbOperator, "address1")
+	require.NoError(t, err)
+	require.Equal(t, "300", reward.Amount)
+
+	// Ranges covered by the deltas are rebuilt.
+	_, err = db.ReplaceELRewards(dbOperator, 11, 12, []*db.ELReward{
+		{Address: "address1", Amount: "20", LastUpdateHeight: 12},
+	}, []*db.ELRewardDelta{
+		{Address: "address1", BlockHeight: 12, Amount: "20"},
+	})
+	require.NoError(t, err)
+
+	reward, err = db.GetELRewards(dbOperator, "address1")
+	require.NoError(t, err)
+	require.Equal(t, "320", reward.Amount)
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Validator is a validator registered through the CreateValidator event of the staking contract,
+// the first event wins if the same public key is registered twice.
+type Validator struct {
+	ID                      uint64    `gorm:"primarykey"`
+	CmpPubkey               string    `gorm:"not null;column:cmp_pubkey;index:idx_validator_cmp_pubkey,unique"` // Hex with 0x prefix
+	EVMAddress              string    `gorm:"not null;column:evm_address;index:idx_validator_evm_address"`      // To lower case
+	ConsensusAddress        string    `gorm:"not null;column:consensus_address;index:idx_validator_consensus_address"`
+	OperatorAddress         string    `gorm:"not null;column:operator_address"` // To lower case
+	Moniker                 string    `gorm:"not null;column:moniker"`
+	StakeAmount             string    `gorm:"not null;column:stake_amount;type:numeric"` // Initial stake in wei
+	SupportTokenType        int       `gorm:"not null;column:support_token_type"`        // 0: locked, 1: unlocked
+	CommissionRate          int64     `gorm:"not null;column:commission_rate"`           // Latest, in basis points
+	MaxCommissionRate       int64     `gorm:"not null;column:max_commission_rate"`
+	MaxCommissionChangeRate int64     `gorm:"not null;column:max_commission_change_rate"`
+	TxHash                  string    `gorm:"not null;column:tx_hash"`
+	BlockHeight             int64     `gorm:"not null;column:block_height"`
+	BlockHash               string    `gorm:"not null;column:block_hash"`
+	BlockTime               time.Time `gorm:"not null;column:block_time"`
+}
+
+func (Validator) TableName() string {
+	return "validators"
+}
+
+// ValidatorCommissionHist is a commission rate set by a CreateValidator or UpdateValidatorCommission event.
+type ValidatorCommissionHist struct {
+	ID             uint64    `gorm:"primarykey"`
+	EVMAddress     string    `gorm:"not null;column:evm_address;index:idx_validator_commission_hist_evm_address_block_height,priority:1"` // To lower case
+	CommissionRate int64     `gorm:"not null;column:commission_rate"`                                                                     // In basis points
+	TxHash         string    `gorm:"not null;column:tx_hash;index:idx_validator_commission_hist_tx_hash_log_index,priority:1,unique"`
+	LogIndex       uint      `gorm:"not null;column:log_index;index:idx_validator_commission_hist_tx_hash_log_index,priority:2,unique"`
+	BlockHeight    int64     `gorm:"not null;column:block_height;index:idx_validator_commission_hist_evm_address_block_height,priority:2"`
+	BlockHash      string    `gorm:"not null;column:block_hash"`
+	BlockTime      time.Time `gorm:"not null;column:block_time"`
+}
+
+func (ValidatorCommissionHist) TableName() string {
+	return "validator_commission_hists"
+}
+
+func BatchCreateValidators(db *gorm.DB, indexer string, validators []*Validator, hists []*ValidatorCommissionHist, height int64) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := createValidators(tx, validators, hists); err != nil {
+			return err
+		}
+
+		return UpdateIndexPoint(tx, indexer, height)
+	})
+}
+
+func createValidators(tx *gorm.DB, validators []*Validator, hists []*ValidatorCommissionHist) error {
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cmp_pubkey"}},
+		DoNothing: true,
+	}).CreateInBatches(validators, 100).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tx_hash"}, {Name: "log_index"}},
+		UpdateAll: true,
+	}).CreateInBatches(hists, 100).Error; err != nil {
+		return err
+	}
+
+	if len(validators) == 0 && len(hists) == 0 {
+		return nil
+	}
+
+	return refreshValidatorCommissionRates(tx)
+}
+
+// refreshValidatorCommissionRates sets the commission rate of the validators to the latest one of their history.
+func refreshValidatorCommissionRates(tx *gorm.DB) error {
+	return tx.Exec(`
+		UPDATE validators SET
+			commission_rate = COALESCE((
+				SELECT h.commission_rate FROM validator_commission_hists AS h
+				WHERE h.evm_address = validators.evm_address
+				ORDER BY h.block_height DESC, h.log_index DESC
+				LIMIT 1
+			), commission_rate)`,
+	).Error
+}
+
+func GetValidators(db *gorm.DB) ([]*Validator, error) {
+	var validators []*Validator
+	if err := db.Order("block_height ASC, id ASC").Find(&validators).Error; err != nil {
+		return nil, err
+	}
+
+	return validators, nil
+}
+
+func GetValidator(db *gorm.DB, evmAddr string) (*Validator, error) {
+	var validator Validator
+	if err := db.Where("evm_address = ?", evmAddr).Order("block_height ASC, id ASC").First(&validator).Error; err != nil {
+		return nil, err
+	}
+
+	return &validator, nil
+}
+
+// GetValidatorCommissionHists returns the commission rates set for the validator in chain order.
+func GetValidatorCommissionHists(db *gorm.DB, evmAddr string) ([]*ValidatorCommissionHist, error) {
+	var hists []*ValidatorCommissionHist
+	if err := db.Where("evm_address = ?", evmAddr).Order("block_height ASC, log_index ASC").Find(&hists).Error; err != nil {
+		return nil, err
+	}
+
+	return hists, nil
+}
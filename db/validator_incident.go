@@ -0,0 +1,85 @@
+package db
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ValidatorIncident is a streak of consecutive blocks missed by a validator of the active set. Streaks are only
+// stored once they reach util.IncidentMinMissedBlocks, the indexer keeps the shorter ones.
+type ValidatorIncident struct {
+	ID          uint64    `gorm:"primarykey"`
+	Validator   string    `gorm:"not null;column:validator;index:idx_validator_incident_validator_start_height,priority:1,unique"` // To lower case
+	StartHeight int64     `gorm:"not null;column:start_height;index:idx_validator_incident_validator_start_height,priority:2,unique"`
+	EndHeight   int64     `gorm:"not null;column:end_height"` // Last missed block
+	BlockCount  int64     `gorm:"not null;column:block_count"`
+	StartTime   time.Time `gorm:"not null;column:start_time"`
+	EndTime     time.Time `gorm:"not null;column:end_time"`
+	Ongoing     bool      `gorm:"not null;column:ongoing;index:idx_validator_incident_ongoing"` // Whether the validator is still missing blocks
+}
+
+func (ValidatorIncident) TableName() string {
+	return "validator_incidents"
+}
+
+// upsertValidatorIncidents stores the new incidents and updates the ones carried over from a previous range.
+func upsertValidatorIncidents(tx *gorm.DB, incidents []*ValidatorIncident) error {
+	if len(incidents) == 0 {
+		return nil
+	}
+
+	created := make([]*ValidatorIncident, 0, len(incidents))
+	for _, incident := range incidents {
+		if incident.ID == 0 {
+			created = append(created, incident)
+		} else if err := tx.Save(incident).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(created) == 0 {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "validator"}, {Name: "start_height"}},
+		DoUpdates: clause.AssignmentColumns([]string{"end_height", "block_count", "end_time", "ongoing"}),
+	}).CreateInBatches(created, 100).Error
+}
+
+// GetOngoingValidatorIncidents returns the incidents still going on as of the last indexed block.
+func GetOngoingValidatorIncidents(db *gorm.DB) ([]*ValidatorIncident, error) {
+	var incidents []*ValidatorIncident
+	if err := db.Where("ongoing = ?", true).Find(&incidents).Error; err != nil {
+		return nil, err
+	}
+
+	return incidents, nil
+}
+
+// GetValidatorIncidents returns the incidents of the validator, latest first.
+func GetValidatorIncidents(db *gorm.DB, validator string, page, perPage int) ([]*ValidatorIncident, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+	offset := (page - 1) * perPage
+
+	query := db.Model(&ValidatorIncident{}).Where("validator = ?", validator)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var incidents []*ValidatorIncident
+	if err := query.Order("start_height DESC").Limit(perPage).Offset(offset).Find(&incidents).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return incidents, total, nil
+}
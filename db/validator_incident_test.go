@@ -0,0 +1,101 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/util"
+)
+
+func TestValidatorIncident(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidator{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorVote{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorUptimeDaily{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorIncident{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	indexerName := "cl_validator_vote"
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     indexerName,
+		BlockHeight: 0,
+	}))
+
+	// A streak long enough to be an incident, still going on.
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+		Incidents: []*db.ValidatorIncident{
+			{Validator: "0xevm1", StartHeight: 1, EndHeight: util.IncidentMinMissedBlocks, BlockCount: util.IncidentMinMissedBlocks, StartTime: time.Unix(100, 0), EndTime: time.Unix(200, 0), Ongoing: true},
+		},
+	}, util.IncidentMinMissedBlocks))
+
+	// A second incident going on.
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+		Incidents: []*db.ValidatorIncident{
+			{Validator: "0xevm2", StartHeight: 2, EndHeight: 11, BlockCount: util.IncidentMinMissedBlocks, StartTime: time.Unix(110, 0), EndTime: time.Unix(210, 0), Ongoing: true},
+		},
+	}, 11))
+
+	ongoing, err := db.GetOngoingValidatorIncidents(dbOperator)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ongoing))
+
+	// Extend the streak carried over and end it.
+	var streak *db.ValidatorIncident
+	for _, incident := range ongoing {
+		if incident.Validator == "0xevm1" {
+			streak = incident
+		}
+	}
+	require.NotNil(t, streak)
+	streak.EndHeight = 12
+	streak.BlockCount = 12
+	streak.EndTime = time.Unix(220, 0)
+	streak.Ongoing = false
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+		Incidents: []*db.ValidatorIncident{streak},
+	}, 13))
+
+	incidents, total, err := db.GetValidatorIncidents(dbOperator, "0xevm1", 1, 100)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, int64(1), incidents[0].StartHeight)
+	require.Equal(t, int64(12), incidents[0].EndHeight)
+	require.Equal(t, int64(12), incidents[0].BlockCount)
+	require.False(t, incidents[0].Ongoing)
+
+	incidents, total, err = db.GetValidatorIncidents(dbOperator, "0xevm2", 1, 100)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	require.True(t, incidents[0].Ongoing)
+
+	ongoing, err = db.GetOngoingValidatorIncidents(dbOperator)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(ongoing))
+	require.Equal(t, "0xevm2", ongoing[0].Validator)
+
+	t.Run("GetLastCLValidatorVotes", func(t *testing.T) {
+		require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+			Votes: []*db.CLValidatorVote{
+				{Validator: "0xevm1", BlockHeight: 14},
+				{Validator: "0xevm1", BlockHeight: 15},
+				{Validator: "0xevm3", BlockHeight: 14},
+				{Validator: "0xevm3", BlockHeight: 16},
+			},
+		}, 16))
+
+		lastVotes, err := db.GetLastCLValidatorVotes(dbOperator, []string{"0xevm1", "0xevm2", "0xevm3"}, 16)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{"0xevm1": 15, "0xevm3": 14}, lastVotes)
+	})
+}
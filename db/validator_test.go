@@ -0,0 +1,104 @@
+package db_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestValidators(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.Validator{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorCommissionHist{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	indexerName := "el_validator"
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     indexerName,
+		BlockHeight: 0,
+	}))
+
+	validators := []*db.Validator{
+		{
+			CmpPubkey:        "0xpubkey1",
+			EVMAddress:       "address1",
+			ConsensusAddress: "CONSENSUS1",
+			Moniker:          "validator1",
+			StakeAmount:      "1024",
+			CommissionRate:   1000,
+			TxHash:           "tx_hash1",
+			BlockHeight:      1,
+			BlockTime:        time.Unix(100, 0),
+		},
+	}
+	hists := []*db.ValidatorCommissionHist{
+		{EVMAddress: "address1", CommissionRate: 1000, TxHash: "tx_hash1", BlockHeight: 1, BlockTime: time.Unix(100, 0)},
+	}
+	require.NoError(t, db.BatchCreateValidators(dbOperator, indexerName, validators, hists, 1))
+
+	t.Run("commission update", func(t *testing.T) {
+		hists := []*db.ValidatorCommissionHist{
+			{EVMAddress: "address1", CommissionRate: 1500, TxHash: "tx_hash2", BlockHeight: 2, BlockTime: time.Unix(200, 0)},
+			{EVMAddress: "address1", CommissionRate: 1200, TxHash: "tx_hash2", LogIndex: 1, BlockHeight: 2, BlockTime: time.Unix(200, 0)},
+		}
+		require.NoError(t, db.BatchCreateValidators(dbOperator, indexerName, nil, hists, 2))
+
+		validator, err := db.GetValidator(dbOperator, "address1")
+		require.NoError(t, err)
+		require.Equal(t, int64(1200), validator.CommissionRate)
+
+		commissionHists, err := db.GetValidatorCommissionHists(dbOperator, "address1")
+		require.NoError(t, err)
+		require.Equal(t, 3, len(commissionHists))
+		require.Equal(t, int64(1000), commissionHists[0].CommissionRate)
+		require.Equal(t, int64(1200), commissionHists[2].CommissionRate)
+	})
+
+	t.Run("duplicate registration", func(t *testing.T) {
+		validators := []*db.Validator{
+			{
+				CmpPubkey:        "0xpubkey1",
+				EVMAddress:       "address1",
+				ConsensusAddress: "CONSENSUS1",
+				Moniker:          "validator1-dup",
+				StakeAmount:      "2048",
+				TxHash:           "tx_hash3",
+				BlockHeight:      3,
+				BlockTime:        time.Unix(300, 0),
+			},
+		}
+		require.NoError(t, db.BatchCreateValidators(dbOperator, indexerName, validators, nil, 3))
+
+		allValidators, err := db.GetValidators(dbOperator)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(allValidators))
+		require.Equal(t, "validator1", allValidators[0].Moniker)
+		require.Equal(t, int64(1200), allValidators[0].CommissionRate)
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		hists := []*db.ValidatorCommissionHist{
+			{EVMAddress: "address1", CommissionRate: 1100, TxHash: "tx_hash2", BlockHeight: 2, BlockTime: time.Unix(200, 0)},
+		}
+		require.NoError(t, db.ReplaceValidators(dbOperator, 2, 3, nil, hists))
+
+		validator, err := db.GetValidator(dbOperator, "address1")
+		require.NoError(t, err)
+		require.Equal(t, int64(1100), validator.CommissionRate)
+
+		commissionHists, err := db.GetValidatorCommissionHists(dbOperator, "address1")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commissionHists))
+	})
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ValidatorUptimeDaily is the signing record of a validator over the blocks of a UTC day it was in the active
+// set for. It outlives the votes, which are pruned out of the uptime window.
+type ValidatorUptimeDaily struct {
+	ID           uint64 `gorm:"primarykey"`
+	Validator    string `gorm:"not null;column:validator;index:idx_validator_uptime_daily_validator_day,priority:1,unique"` // To lower case
+	Day          int64  `gorm:"not null;column:day;index:idx_validator_uptime_daily_validator_day,priority:2,unique"`       // Unix timestamp of the start of the day
+	SignedBlocks int64  `gorm:"not null;column:signed_blocks"`
+	MissedBlocks int64  `gorm:"not null;column:missed_blocks"`
+	TotalBlocks  int64  `gorm:"not null;column:total_blocks"`
+}
+
+func (ValidatorUptimeDaily) TableName() string {
+	return "validator_uptime_daily"
+}
+
+// addValidatorUptimes adds the blocks of the rollups to the stored ones of the same validator and day.
+func addValidatorUptimes(tx *gorm.DB, uptimes []*ValidatorUptimeDaily) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "validator"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"signed_blocks": gorm.Expr("validator_uptime_daily.signed_blocks + excluded.signed_blocks"),
+			"missed_blocks": gorm.Expr("validator_uptime_daily.missed_blocks + excluded.missed_blocks"),
+			"total_blocks":  gorm.Expr("validator_uptime_daily.total_blocks + excluded.total_blocks"),
+		}),
+	}).CreateInBatches(uptimes, 100).Error
+}
+
+// GetValidatorUptimeDaily returns the daily rollups of the validator since the given unix timestamp in time order.
+func GetValidatorUptimeDaily(db *gorm.DB, validator string, since int64) ([]*ValidatorUptimeDaily, error) {
+	var uptimes []*ValidatorUptimeDaily
+	if err := db.Where("validator = ? AND day >= ?", validator, since).Order("day ASC").Find(&uptimes).Error; err != nil {
+		return nil, err
+	}
+
+	return uptimes, nil
+}
@@ -0,0 +1,70 @@
+package db_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestValidatorUptimeDaily(t *testing.T) {
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	defer dbConn.Close()
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidator{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.CLValidatorVote{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorUptimeDaily{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.ValidatorIncident{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+
+	indexerName := "cl_validator_vote"
+	require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{
+		Indexer:     indexerName,
+		BlockHeight: 0,
+	}))
+
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+		Votes: []*db.CLValidatorVote{{Validator: "0xevm1", BlockHeight: 1}},
+		Uptimes: []*db.ValidatorUptimeDaily{
+			{Validator: "0xevm1", Day: 0, SignedBlocks: 1, TotalBlocks: 1},
+			{Validator: "0xevm2", Day: 0, MissedBlocks: 1, TotalBlocks: 1},
+		},
+	}, 1))
+
+	// The rollups of the same day are added up, the ones of the votes pruned out of the window are kept.
+	const uptimeWindow = 28800
+	height := int64(1 + uptimeWindow)
+	require.NoError(t, db.BatchUpdateCLValidatorVotes(dbOperator, indexerName, &db.CLValidatorVoteBatch{
+		Votes: []*db.CLValidatorVote{{Validator: "0xevm1", BlockHeight: height}},
+		Uptimes: []*db.ValidatorUptimeDaily{
+			{Validator: "0xevm1", Day: 0, MissedBlocks: 1, TotalBlocks: 1},
+			{Validator: "0xevm1", Day: 86400, SignedBlocks: 1, TotalBlocks: 1},
+		},
+		UptimeWindow: uptimeWindow,
+	}, height))
+
+	votes, err := db.GetCLValidatorsVotes(dbOperator, "0xevm1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), votes["0xevm1"])
+
+	uptimes, err := db.GetValidatorUptimeDaily(dbOperator, "0xevm1", 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(uptimes))
+	require.Equal(t, int64(0), uptimes[0].Day)
+	require.Equal(t, int64(1), uptimes[0].SignedBlocks)
+	require.Equal(t, int64(1), uptimes[0].MissedBlocks)
+	require.Equal(t, int64(2), uptimes[0].TotalBlocks)
+	require.Equal(t, int64(86400), uptimes[1].Day)
+
+	uptimes, err = db.GetValidatorUptimeDaily(dbOperator, "0xevm1", 86400)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(uptimes))
+	require.Equal(t, int64(1), uptimes[0].SignedBlocks)
+}
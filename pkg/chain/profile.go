@@ -0,0 +1,289 @@
+package chain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	cmttypes "github.com/cometbft/cometbft/types"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/piplabs/story-staking-api/pkg/indexer/contract/iptokenstaking"
+	"github.com/piplabs/story-staking-api/pkg/util"
+)
+
+// The defaults are the values of the network the service was first built for.
+const (
+	DefaultGenesisBlockHeight = 1
+	DefaultGenesisStakeAmount = 8000000 // gwei
+	DefaultUptimeWindow       = 28800
+)
+
+var DefaultGenesisTime = time.Date(2025, 1, 19, 15, 0, 0, 0, time.UTC)
+
+// NetworkAeneid is the name of the Story testnet, the only network known to emit staking events without an amount.
+const NetworkAeneid = "aeneid"
+
+// Profile describes the Story network the service indexes, set in the `[chain]` section. The unset fields are
+// derived from the genesis file if any, or fall back to the defaults.
+type Profile struct {
+	// Name of the network, e.g. mainnet, aeneid or localnet, from `chain_id` of the genesis file. It selects the
+	// default quirks of the network.
+	Name string `toml:"name"`
+	// GenesisFile is the genesis.json of the network, relative to the home directory.
+	GenesisFile string `toml:"genesis_file"`
+	// GenesisTime is the time of the genesis block, from `genesis_time` of the genesis file.
+	GenesisTime time.Time `toml:"genesis_time"`
+	// GenesisBlockHeight is the first block height, from `initial_height` of the genesis file.
+	GenesisBlockHeight int64 `toml:"genesis_block_height"`
+	// GenesisStakeAmount is the total stake at genesis in gwei, from the genesis validators and transactions.
+	GenesisStakeAmount int64 `toml:"genesis_stake_amount"`
+	// GenesisValidators are the validators bonded at genesis with their stake, from the genesis validators and
+	// transactions.
+	GenesisValidators []GenesisValidator `toml:"genesis_validators"`
+	// StakingContractAddress is the address of the IPTokenStaking contract.
+	StakingContractAddress string `toml:"staking_contract_address"`
+	// UptimeWindow is the number of blocks the uptime of validators is measured over, from the
+	// `signed_blocks_window` slashing param of the genesis file.
+	UptimeWindow int64 `toml:"uptime_window"`
+	// Quirks are the deviations of the network from the expected chain behavior.
+	Quirks Quirks `toml:"quirks"`
+}
+
+// GenesisValidator is a validator bonded at genesis.
+type GenesisValidator struct {
+	// Address is the EVM address of the validator.
+	Address string `toml:"address"`
+	// StakeAmount is the stake of the validator at genesis in gwei.
+	StakeAmount int64 `toml:"stake_amount"`
+}
+
+type Quirks struct {
+	// EmptyStakeAmounts counts staking events without an amount as zero instead of failing, seen on aeneid.
+	// Defaults to true on aeneid only.
+	EmptyStakeAmounts *bool `toml:"empty_stake_amounts"`
+}
+
+func (q Quirks) AllowEmptyStakeAmounts() bool {
+	return q.EmptyStakeAmounts != nil && *q.EmptyStakeAmounts
+}
+
+// ContractAddress returns the address of the staking contract, the profile must be loaded.
+func (p Profile) ContractAddress() common.Address {
+	return common.HexToAddress(p.StakingContractAddress)
+}
+
+func (p Profile) Validate() error {
+	if p.StakingContractAddress != "" && !common.IsHexAddress(p.StakingContractAddress) {
+		return fmt.Errorf("invalid staking contract address: %s", p.StakingContractAddress)
+	}
+
+	if p.GenesisBlockHeight < 0 {
+		return fmt.Errorf("invalid genesis block height: %d", p.GenesisBlockHeight)
+	}
+
+	if p.GenesisStakeAmount < 0 {
+		return fmt.Errorf("invalid genesis stake amount: %d", p.GenesisStakeAmount)
+	}
+
+	if p.UptimeWindow < 0 {
+		return fmt.Errorf("invalid uptime window: %d", p.UptimeWindow)
+	}
+
+	for _, v := range p.GenesisValidators {
+		if !common.IsHexAddress(v.Address) {
+			return fmt.Errorf("invalid genesis validator address: %s", v.Address)
+		}
+
+		if v.StakeAmount < 0 {
+			return fmt.Errorf("invalid stake amount of genesis validator %s: %d", v.Address, v.StakeAmount)
+		}
+	}
+
+	return nil
+}
+
+// Load returns a copy of the profile with the unset fields filled from the genesis file, resolved against dir,
+// and then with the defaults.
+func (p Profile) Load(dir string) (Profile, error) {
+	if p.GenesisFile != "" {
+		genesisFile := p.GenesisFile
+		if !filepath.IsAbs(genesisFile) {
+			genesisFile = filepath.Join(dir, genesisFile)
+		}
+
+		genesis, err := loadGenesis(genesisFile)
+		if err != nil {
+			return p, fmt.Errorf("load genesis file %s failed: %w", genesisFile, err)
+		}
+
+		if p.Name == "" {
+			p.Name = genesis.Name
+		}
+		if p.GenesisTime.IsZero() {
+			p.GenesisTime = genesis.GenesisTime
+		}
+		if p.GenesisBlockHeight == 0 {
+			p.GenesisBlockHeight = genesis.GenesisBlockHeight
+		}
+		if p.GenesisStakeAmount == 0 {
+			p.GenesisStakeAmount = genesis.GenesisStakeAmount
+		}
+		if len(p.GenesisValidators) == 0 {
+			p.GenesisValidators = genesis.GenesisValidators
+		}
+		if p.UptimeWindow == 0 {
+			p.UptimeWindow = genesis.UptimeWindow
+		}
+	}
+
+	if p.GenesisTime.IsZero() {
+		p.GenesisTime = DefaultGenesisTime
+	}
+
+	if p.GenesisBlockHeight == 0 {
+		p.GenesisBlockHeight = DefaultGenesisBlockHeight
+	}
+
+	if p.GenesisStakeAmount == 0 {
+		p.GenesisStakeAmount = DefaultGenesisStakeAmount
+	}
+
+	if p.StakingContractAddress == "" {
+		p.StakingContractAddress = iptokenstaking.ContractAddress.Hex()
+	}
+
+	if p.UptimeWindow == 0 {
+		p.UptimeWindow = DefaultUptimeWindow
+	}
+
+	if p.Quirks.EmptyStakeAmounts == nil {
+		allowEmpty := p.Name == NetworkAeneid
+		p.Quirks.EmptyStakeAmounts = &allowEmpty
+	}
+
+	validators := make([]GenesisValidator, 0, len(p.GenesisValidators))
+	for _, v := range p.GenesisValidators {
+		validators = append(validators, GenesisValidator{Address: strings.ToLower(v.Address), StakeAmount: v.StakeAmount})
+	}
+	p.GenesisValidators = validators
+
+	return p, nil
+}
+
+// appState is the part of the app state of the genesis file the profile is derived from.
+type appState struct {
+	Slashing struct {
+		Params struct {
+			SignedBlocksWindow string `json:"signed_blocks_window"`
+		} `json:"params"`
+	} `json:"slashing"`
+	Staking struct {
+		Validators []struct {
+			ConsensusPubkey pubKey `json:"consensus_pubkey"`
+			Tokens          string `json:"tokens"`
+		} `json:"validators"`
+	} `json:"staking"`
+	Genutil struct {
+		GenTxs []struct {
+			Body struct {
+				Messages []struct {
+					Type   string `json:"@type"`
+					Pubkey pubKey `json:"pubkey"`
+					Value  struct {
+						Amount string `json:"amount"`
+					} `json:"value"`
+				} `json:"messages"`
+			} `json:"body"`
+		} `json:"gen_txs"`
+	} `json:"genutil"`
+}
+
+type pubKey struct {
+	Key string `json:"key"` // Base64 of the compressed secp256k1 public key
+}
+
+// evmAddress returns the EVM address of the validator of the public key.
+func (k pubKey) evmAddress() (string, error) {
+	key, err := base64.StdEncoding.DecodeString(k.Key)
+	if err != nil {
+		return "", fmt.Errorf("decode public key failed: %w", err)
+	}
+
+	addr, err := util.CmpPubKeyToEVMAddress(key)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(addr.Hex()), nil
+}
+
+// loadGenesis returns the profile described by the genesis file, the fields the file does not set are left unset.
+func loadGenesis(file string) (Profile, error) {
+	var p Profile
+
+	genDoc, err := cmttypes.GenesisDocFromFile(file)
+	if err != nil {
+		return p, err
+	}
+
+	p.Name = genDoc.ChainID
+	p.GenesisTime = genDoc.GenesisTime.UTC()
+	p.GenesisBlockHeight = genDoc.InitialHeight
+
+	if len(genDoc.AppState) == 0 {
+		return p, nil
+	}
+
+	var state appState
+	if err := json.Unmarshal(genDoc.AppState, &state); err != nil {
+		return p, fmt.Errorf("decode app state failed: %w", err)
+	}
+
+	if window := state.Slashing.Params.SignedBlocksWindow; window != "" {
+		if p.UptimeWindow, err = strconv.ParseInt(window, 10, 64); err != nil {
+			return p, fmt.Errorf("parse signed blocks window failed: %w", err)
+		}
+	}
+
+	// The validators bonded at genesis, either imported or created by the genesis transactions.
+	for _, v := range state.Staking.Validators {
+		tokens, err := strconv.ParseInt(v.Tokens, 10, 64)
+		if err != nil {
+			return p, fmt.Errorf("parse validator tokens failed: %w", err)
+		}
+		p.GenesisStakeAmount += tokens
+
+		addr, err := v.ConsensusPubkey.evmAddress()
+		if err != nil {
+			return p, fmt.Errorf("derive validator address failed: %w", err)
+		}
+		p.GenesisValidators = append(p.GenesisValidators, GenesisValidator{Address: addr, StakeAmount: tokens})
+	}
+
+	for _, tx := range state.Genutil.GenTxs {
+		for _, msg := range tx.Body.Messages {
+			if !strings.HasSuffix(msg.Type, ".MsgCreateValidator") {
+				continue
+			}
+
+			amount, err := strconv.ParseInt(msg.Value.Amount, 10, 64)
+			if err != nil {
+				return p, fmt.Errorf("parse self-delegation amount failed: %w", err)
+			}
+			p.GenesisStakeAmount += amount
+
+			addr, err := msg.Pubkey.evmAddress()
+			if err != nil {
+				return p, fmt.Errorf("derive validator address failed: %w", err)
+			}
+			p.GenesisValidators = append(p.GenesisValidators, GenesisValidator{Address: addr, StakeAmount: amount})
+		}
+	}
+
+	return p, nil
+}
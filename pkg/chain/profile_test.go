@@ -0,0 +1,95 @@
+package chain_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/piplabs/story-staking-api/pkg/chain"
+	"github.com/piplabs/story-staking-api/pkg/indexer/contract/iptokenstaking"
+)
+
+const genesis = `{
+  "genesis_time": "2025-02-01T00:00:00Z",
+  "chain_id": "localnet-1",
+  "initial_height": "10",
+  "app_state": {
+    "slashing": {"params": {"signed_blocks_window": "100"}},
+    "staking": {"validators": [
+      {"consensus_pubkey": {"key": "Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY"}, "tokens": "1024"},
+      {"consensus_pubkey": {"key": "AsYEf5RB7X1tMEVAbpXAfNhcd45LjO88p6usCblccJ7l"}, "tokens": "2048"}
+    ]},
+    "genutil": {"gen_txs": [{"body": {"messages": [
+      {"@type": "/cosmos.staking.v1beta1.MsgCreateValidator", "pubkey": {"key": "AvkwigGSWMMQSTRPhfidUim1MchFg2+ZsIYB8RO84Db5"}, "value": {"denom": "stake", "amount": "512"}}
+    ]}}]}
+  }
+}`
+
+func TestProfileLoad(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		profile, err := chain.Profile{}.Load(".")
+		require.NoError(t, err)
+		require.Equal(t, chain.DefaultGenesisTime, profile.GenesisTime)
+		require.Equal(t, int64(chain.DefaultGenesisBlockHeight), profile.GenesisBlockHeight)
+		require.Equal(t, int64(chain.DefaultGenesisStakeAmount), profile.GenesisStakeAmount)
+		require.Equal(t, int64(chain.DefaultUptimeWindow), profile.UptimeWindow)
+		require.Equal(t, iptokenstaking.ContractAddress, profile.ContractAddress())
+		require.False(t, profile.Quirks.AllowEmptyStakeAmounts())
+	})
+
+	t.Run("aeneid", func(t *testing.T) {
+		profile, err := chain.Profile{Name: chain.NetworkAeneid}.Load(".")
+		require.NoError(t, err)
+		require.True(t, profile.Quirks.AllowEmptyStakeAmounts())
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "genesis.json"), []byte(strings.Replace(genesis, "localnet-1", chain.NetworkAeneid, 1)), 0o600))
+
+		profile, err = chain.Profile{GenesisFile: "genesis.json"}.Load(dir)
+		require.NoError(t, err)
+		require.Equal(t, chain.NetworkAeneid, profile.Name)
+		require.True(t, profile.Quirks.AllowEmptyStakeAmounts())
+	})
+
+	t.Run("genesis file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "genesis.json"), []byte(genesis), 0o600))
+
+		allowEmpty := true
+		profile, err := chain.Profile{
+			GenesisFile:  "genesis.json",
+			UptimeWindow: 200,
+			Quirks:       chain.Quirks{EmptyStakeAmounts: &allowEmpty},
+		}.Load(dir)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), profile.GenesisTime)
+		require.Equal(t, int64(10), profile.GenesisBlockHeight)
+		require.Equal(t, int64(3584), profile.GenesisStakeAmount)
+		require.Equal(t, []chain.GenesisValidator{
+			{Address: "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", StakeAmount: 1024},
+			{Address: "0x2b5ad5c4795c026514f8317c7a215e218dccd6cf", StakeAmount: 2048},
+			{Address: "0x6813eb9362372eef6200f3b1dbc3f819671cba69", StakeAmount: 512},
+		}, profile.GenesisValidators)
+		require.Equal(t, "localnet-1", profile.Name)
+
+		// The configured values win over the genesis file.
+		require.True(t, profile.Quirks.AllowEmptyStakeAmounts())
+		require.Equal(t, int64(200), profile.UptimeWindow)
+	})
+
+	t.Run("missing genesis file", func(t *testing.T) {
+		_, err := chain.Profile{GenesisFile: "genesis.json"}.Load(t.TempDir())
+		require.Error(t, err)
+	})
+}
+
+func TestProfileValidate(t *testing.T) {
+	require.NoError(t, chain.Profile{}.Validate())
+	require.Error(t, chain.Profile{StakingContractAddress: "0x1234"}.Validate())
+	require.Error(t, chain.Profile{UptimeWindow: -1}.Validate())
+	require.Error(t, chain.Profile{GenesisValidators: []chain.GenesisValidator{{Address: "0x1234"}}}.Validate())
+}
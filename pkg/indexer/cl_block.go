@@ -2,17 +2,20 @@ package indexer
 
 import (
 	"context"
-	"time"
 
 	comethttp "github.com/cometbft/cometbft/rpc/client/http"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cometbft/cometbft/types"
 	redis "github.com/redis/go-redis/v9"
-	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"github.com/piplabs/story-staking-api/db"
 )
 
-var _ Indexer = (*CLBlockIndexer)(nil)
+var (
+	_ HeadSubscriber = (*CLBlockIndexer)(nil)
+	_ Reindexer      = (*CLBlockIndexer)(nil)
+)
 
 type CLBlockIndexer struct {
 	ctx context.Context
@@ -20,10 +23,13 @@ type CLBlockIndexer struct {
 	dbOperator    *gorm.DB
 	cacheOperator *redis.Client
 
+	rpcEndpoint string
 	cometClient *comethttp.HTTP
+
+	fetchConcurrency int
 }
 
-func NewCLBlockIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string) (*CLBlockIndexer, error) {
+func NewCLBlockIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, fetchConcurrency int) (*CLBlockIndexer, error) {
 	cometClient, err := comethttp.New(rpcEndpoint, "")
 	if err != nil {
 		return nil, err
@@ -35,81 +41,60 @@ func NewCLBlockIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *
 		dbOperator:    dbOperator,
 		cacheOperator: cacheOperator,
 
+		rpcEndpoint: rpcEndpoint,
 		cometClient: cometClient,
+
+		fetchConcurrency: fetchConcurrency,
 	}, nil
 }
 
 func (c *CLBlockIndexer) Name() string {
-	return "cl_block"
+	return NameCLBlock
+}
+
+func (c *CLBlockIndexer) ChainHead() (int64, error) {
+	return clChainHead(c.ctx, c.cometClient)
+}
+
+func (c *CLBlockIndexer) SubscribeHead(ctx context.Context) (<-chan int64, error) {
+	return subscribeCLHead(ctx, c.rpcEndpoint, c.Name(), types.EventQueryNewBlock.String())
 }
 
-func (c *CLBlockIndexer) Run() {
-	log.Info().Str("indexer", c.Name()).Msg("Start indexing")
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		case <-ticker.C:
-			indexPoint, err := db.GetIndexPoint(c.dbOperator, c.Name())
-			if err != nil {
-				log.Error().Err(err).Str("indexer", c.Name()).Msg("get index point failed")
-				continue
-			}
-
-			latestBlk, err := c.cometClient.Block(c.ctx, nil)
-			if err != nil {
-				log.Error().Err(err).Str("indexer", c.Name()).Msg("get latest cl block failed")
-				continue
-			}
-
-			if indexPoint.BlockHeight+10 > latestBlk.Block.Height {
-				continue
-			}
-
-			if err := c.index(indexPoint.BlockHeight+1, latestBlk.Block.Height); err != nil {
-				log.Error().Err(err).
-					Str("indexer", c.Name()).
-					Int64("from", indexPoint.BlockHeight+1).
-					Int64("to", latestBlk.Block.Height).
-					Msg("index cl block failed")
-			}
-		}
+func (c *CLBlockIndexer) Index(from, to int64) error {
+	blocks, err := c.getBlocks(from, to)
+	if err != nil {
+		return err
 	}
+
+	return db.BatchCreateCLBlocks(c.dbOperator, c.Name(), blocks, to)
 }
 
-func (c *CLBlockIndexer) index(from, to int64) error {
-	var blocks []*db.CLBlock
+func (c *CLBlockIndexer) Reindex(from, to int64) error {
+	blocks, err := c.getBlocks(from, to)
+	if err != nil {
+		return err
+	}
+
+	return db.ReplaceCLBlocks(c.dbOperator, from, to, blocks)
+}
 
-	for i := from; i <= to; i++ {
-		blk, err := c.cometClient.Block(c.ctx, &i)
-		if err != nil {
-			return err
-		}
+func (c *CLBlockIndexer) getBlocks(from, to int64) ([]*db.CLBlock, error) {
+	blks, err := fetchBlocks(c.ctx, from, to, c.fetchConcurrency, func(ctx context.Context, height int64) (*coretypes.ResultBlock, error) {
+		return c.cometClient.Block(ctx, &height)
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	blocks := make([]*db.CLBlock, 0, len(blks))
+	for _, blk := range blks {
 		blocks = append(blocks, &db.CLBlock{
 			Height:          blk.Block.Height,
 			Hash:            blk.Block.Hash().String(),
 			ProposerAddress: blk.Block.ProposerAddress.String(),
 			Time:            blk.Block.Time,
 		})
-
-		if len(blocks) > 100 {
-			if err := db.BatchCreateCLBlocks(c.dbOperator, c.Name(), blocks, i); err != nil {
-				return err
-			}
-
-			blocks = make([]*db.CLBlock, 0)
-		}
-	}
-
-	// Handle remaining entries, even if there are no entries, we also need to update the index point.
-	if err := db.BatchCreateCLBlocks(c.dbOperator, c.Name(), blocks, to); err != nil {
-		return err
 	}
 
-	return nil
+	return blocks, nil
 }
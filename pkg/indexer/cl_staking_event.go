@@ -3,21 +3,26 @@ package indexer
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
-	"time"
 
 	abcitypes "github.com/cometbft/cometbft/abci/types"
 	comethttp "github.com/cometbft/cometbft/rpc/client/http"
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
+	"github.com/cometbft/cometbft/types"
 	redis "github.com/redis/go-redis/v9"
-	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"github.com/piplabs/story-staking-api/db"
 	"github.com/piplabs/story-staking-api/pkg/util"
 )
 
-var _ Indexer = (*CLStakingEventIndexer)(nil)
+var (
+	_ HeadSubscriber = (*CLStakingEventIndexer)(nil)
+	_ Reindexer      = (*CLStakingEventIndexer)(nil)
+)
 
 const (
 	EventTypeSetOperatorFailure               = "set_operator_failure"
@@ -42,12 +47,20 @@ const (
 	EventTypeUndelegateSuccess                = "undelegate_success"
 	EventTypeUnjailSuccess                    = "unjail_success"
 
+	// Emitted by the slashing module for slashes, and for jails with only the jailed attribute.
+	EventTypeSlash = "slash"
+
 	AttributeKeyErrorCode          = "error_code"
 	AttributeKeyTxHash             = "tx_hash"
 	AttributeKeyValidatorCmpPubKey = "validator_cmp_pubkey"
 	AttributeKeyAmount             = "amount"
 	AttributeKeySenderAddress      = "sender_address"
 	AttributeKeyDelegatorAddress   = "delegator_addr"
+	AttributeKeyAddress            = "address"
+	AttributeKeyPower              = "power"
+	AttributeKeyReason             = "reason"
+	AttributeKeyJailed             = "jailed"
+	AttributeKeyBurnedCoins        = "burned_coins"
 )
 
 var Event2Type = map[string]string{
@@ -86,10 +99,13 @@ type CLStakingEventIndexer struct {
 	dbOperator    *gorm.DB
 	cacheOperator *redis.Client
 
+	rpcEndpoint string
 	cometClient *comethttp.HTTP
+
+	fetchConcurrency int
 }
 
-func NewCLStakingEventIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string) (*CLStakingEventIndexer, error) {
+func NewCLStakingEventIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, fetchConcurrency int) (*CLStakingEventIndexer, error) {
 	cometClient, err := comethttp.New(rpcEndpoint, "")
 	if err != nil {
 		return nil, err
@@ -101,81 +117,58 @@ func NewCLStakingEventIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOpe
 		dbOperator:    dbOperator,
 		cacheOperator: cacheOperator,
 
+		rpcEndpoint: rpcEndpoint,
 		cometClient: cometClient,
+
+		fetchConcurrency: fetchConcurrency,
 	}, nil
 }
 
 func (c *CLStakingEventIndexer) Name() string {
-	return "cl_staking_event"
+	return NameCLStakingEvent
 }
 
-func (c *CLStakingEventIndexer) Run() {
-	log.Info().Str("indexer", c.Name()).Msg("Start indexing")
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		case <-ticker.C:
-			indexPoint, err := db.GetIndexPoint(c.dbOperator, c.Name())
-			if err != nil {
-				log.Error().Err(err).Str("indexer", c.Name()).Msg("get index point failed")
-				continue
-			}
-
-			latestBlk, err := c.cometClient.Block(c.ctx, nil)
-			if err != nil {
-				log.Error().Err(err).Str("indexer", c.Name()).Msg("get latest cl block failed")
-				continue
-			}
-
-			if indexPoint.BlockHeight+10 > latestBlk.Block.Height {
-				continue
-			}
-
-			if err := c.index(indexPoint.BlockHeight+1, latestBlk.Block.Height); err != nil {
-				log.Error().Err(err).
-					Str("indexer", c.Name()).
-					Int64("from", indexPoint.BlockHeight+1).
-					Int64("to", latestBlk.Block.Height).
-					Msg("index cl staking event failed")
-			}
-		}
-	}
+func (c *CLStakingEventIndexer) ChainHead() (int64, error) {
+	return clChainHead(c.ctx, c.cometClient)
 }
 
-func (c *CLStakingEventIndexer) index(from, to int64) error {
-	start := from
-
-	for start <= to {
-		end := min(start+100, to)
+func (c *CLStakingEventIndexer) SubscribeHead(ctx context.Context) (<-chan int64, error) {
+	return subscribeCLHead(ctx, c.rpcEndpoint, c.Name(), types.EventQueryNewBlockEvents.String())
+}
 
-		stakingEvents, err := c.getStakingEvents(start, end)
-		if err != nil {
-			return err
-		}
+func (c *CLStakingEventIndexer) Index(from, to int64) error {
+	stakingEvents, penalties, err := c.getStakingEvents(from, to)
+	if err != nil {
+		return err
+	}
 
-		if err := db.BatchCreateCLStakingEvents(c.dbOperator, c.Name(), stakingEvents, end); err != nil {
-			return err
-		}
+	return db.BatchCreateCLStakingEvents(c.dbOperator, c.Name(), stakingEvents, penalties, to)
+}
 
-		start = end + 1
+func (c *CLStakingEventIndexer) Reindex(from, to int64) error {
+	stakingEvents, penalties, err := c.getStakingEvents(from, to)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return db.ReplaceCLStakingEvents(c.dbOperator, from, to, stakingEvents, penalties)
 }
 
-func (c *CLStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.CLStakingEvent, error) {
+// getStakingEvents returns the staking events in [from, to] along with the validator penalties.
+func (c *CLStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.CLStakingEvent, []*db.CLValidatorPenalty, error) {
+	blocksResults, err := fetchBlocks(c.ctx, from, to, c.fetchConcurrency, func(ctx context.Context, height int64) (*coretypes.ResultBlockResults, error) {
+		return c.cometClient.BlockResults(ctx, &height)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
 	stakingCLEvents := make([]*db.CLStakingEvent, 0)
+	penalties := make([]*db.CLValidatorPenalty, 0)
 
-	for blkno := from; blkno <= to; blkno++ {
-		blockResults, err := c.cometClient.BlockResults(c.ctx, &blkno)
-		if err != nil {
-			return nil, err
-		}
+	for _, blockResults := range blocksResults {
+		eventIndex := 0
+		penaltyIndex := 0
 
 		blockEvents := make([]abcitypes.Event, 0)
 		for _, tr := range blockResults.TxsResults {
@@ -184,6 +177,22 @@ func (c *CLStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.CLStakin
 		blockEvents = append(blockEvents, blockResults.FinalizeBlockEvents...)
 
 		for _, e := range blockEvents {
+			if e.Type == EventTypeSlash {
+				blkPenalties, err := parsePenalties(e)
+				if err != nil {
+					return nil, nil, eventError(blockResults.Height, e, err)
+				}
+
+				for _, p := range blkPenalties {
+					p.BlockHeight = blockResults.Height
+					p.EventIndex = penaltyIndex
+					penaltyIndex++
+				}
+				penalties = append(penalties, blkPenalties...)
+
+				continue
+			}
+
 			eventType, ok := Event2Type[e.Type]
 			if !ok {
 				continue
@@ -196,11 +205,11 @@ func (c *CLStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.CLStakin
 			case TypeStake, TypeRedelegate, TypeUnstake:
 				delAddr, ok := attrMap[AttributeKeyDelegatorAddress]
 				if !ok {
-					return nil, fmt.Errorf("event %s: delegator address not found", eventType)
+					return nil, nil, eventError(blockResults.Height, e, fmt.Errorf("event %s: delegator address not found", eventType))
 				}
 				senderAddr, ok := attrMap[AttributeKeySenderAddress]
 				if !ok {
-					return nil, fmt.Errorf("event %s: sender address not found", eventType)
+					return nil, nil, eventError(blockResults.Height, e, fmt.Errorf("event %s: sender address not found", eventType))
 				}
 
 				if !strings.EqualFold(delAddr, senderAddr) {
@@ -209,20 +218,20 @@ func (c *CLStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.CLStakin
 			case TypeUnjail:
 				valCmpPubKey, ok := attrMap[AttributeKeyValidatorCmpPubKey]
 				if !ok {
-					return nil, fmt.Errorf("event %s: validator compressed key not found", eventType)
+					return nil, nil, eventError(blockResults.Height, e, fmt.Errorf("event %s: validator compressed key not found", eventType))
 				}
 				senderAddr, ok := attrMap[AttributeKeySenderAddress]
 				if !ok {
-					return nil, fmt.Errorf("event %s: sender address not found", eventType)
+					return nil, nil, eventError(blockResults.Height, e, fmt.Errorf("event %s: sender address not found", eventType))
 				}
 
 				valCmpPubKeyBytes, err := hex.DecodeString(valCmpPubKey)
 				if err != nil {
-					return nil, fmt.Errorf("decode validator compressed key from event %s failed: %w", eventType, err)
+					return nil, nil, eventError(blockResults.Height, e, fmt.Errorf("decode validator compressed key from event %s failed: %w", eventType, err))
 				}
 				valAddr, err := util.CmpPubKeyToEVMAddress(valCmpPubKeyBytes)
 				if err != nil {
-					return nil, fmt.Errorf("convert validator compressed key to address from event %s failed: %w", eventType, err)
+					return nil, nil, eventError(blockResults.Height, e, fmt.Errorf("convert validator compressed key to address from event %s failed: %w", eventType, err))
 				}
 
 				if !strings.EqualFold(valAddr.String(), senderAddr) {
@@ -235,13 +244,81 @@ func (c *CLStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.CLStakin
 			stakingCLEvents = append(stakingCLEvents, &db.CLStakingEvent{
 				ELTxHash:    "0x" + attrMap[AttributeKeyTxHash],
 				EventType:   eventType,
-				BlockHeight: blkno,
+				BlockHeight: blockResults.Height,
 				StatusOK:    !exists,
 				ErrorCode:   errCode,
 				Amount:      attrMap[AttributeKeyAmount],
+				EventIndex:  eventIndex,
 			})
+			eventIndex++
 		}
 	}
 
-	return stakingCLEvents, nil
+	return stakingCLEvents, penalties, nil
+}
+
+// parsePenalties decodes a slash event of the slashing module. The event of a slash carries the jail too if the
+// validator is jailed for it, jails of double signs come in a separate event with only the jailed attribute.
+func parsePenalties(e abcitypes.Event) ([]*db.CLValidatorPenalty, error) {
+	attrMap := attrArray2Map(e.Attributes)
+
+	var power int64
+	if powerStr, ok := attrMap[AttributeKeyPower]; ok {
+		var err error
+		if power, err = strconv.ParseInt(powerStr, 10, 64); err != nil {
+			return nil, fmt.Errorf("parse power failed: %w", err)
+		}
+	}
+
+	penalties := make([]*db.CLValidatorPenalty, 0, 2)
+
+	if addr, ok := attrMap[AttributeKeyAddress]; ok {
+		cometAddr, err := util.ConsAddressToCometAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("convert validator consensus address failed: %w", err)
+		}
+
+		// Coins are formatted as amount followed by denom, empty if nothing is burned.
+		burnedCoins := attrMap[AttributeKeyBurnedCoins]
+		amount := strings.TrimRightFunc(burnedCoins, func(r rune) bool { return r < '0' || r > '9' })
+		if amount == "" {
+			amount = "0"
+		}
+
+		penalties = append(penalties, &db.CLValidatorPenalty{
+			ConsensusAddress: cometAddr,
+			Kind:             PenaltyKindSlash,
+			Reason:           attrMap[AttributeKeyReason],
+			Power:            power,
+			Amount:           amount,
+		})
+	}
+
+	if addr, ok := attrMap[AttributeKeyJailed]; ok {
+		cometAddr, err := util.ConsAddressToCometAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("convert validator consensus address failed: %w", err)
+		}
+
+		penalties = append(penalties, &db.CLValidatorPenalty{
+			ConsensusAddress: cometAddr,
+			Kind:             PenaltyKindJail,
+			Reason:           attrMap[AttributeKeyReason],
+			Power:            power,
+			Amount:           "0",
+		})
+	}
+
+	return penalties, nil
+}
+
+// eventError wraps the error of a staking event that cannot be indexed along with the event.
+func eventError(height int64, event abcitypes.Event, err error) error {
+	attrs, _ := json.Marshal(attrArray2Map(event.Attributes))
+
+	return &BlockError{
+		Height: height,
+		Event:  fmt.Sprintf("%s %s", event.Type, attrs),
+		Err:    err,
+	}
 }
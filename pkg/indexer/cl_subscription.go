@@ -0,0 +1,84 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	comethttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/cometbft/cometbft/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// clSubscriptionTimeout is how long a subscription may stay silent before it is considered dropped.
+	clSubscriptionTimeout = time.Minute
+)
+
+// HeadSubscriber is implemented by indexers that can be pushed new chain heads instead of polling for them.
+type HeadSubscriber interface {
+	Indexer
+	// SubscribeHead streams the heights of new blocks, the channel is closed when the subscription drops.
+	SubscribeHead(ctx context.Context) (<-chan int64, error)
+}
+
+// subscribeCLHead subscribes to a CometBFT event query over websocket and streams the block height of
+// every event. Only the latest height is buffered, as the receiver indexes everything up to it anyway.
+func subscribeCLHead(ctx context.Context, rpcEndpoint, subscriber, query string) (<-chan int64, error) {
+	wsClient, err := comethttp.New(rpcEndpoint, "/websocket")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wsClient.Start(); err != nil {
+		return nil, fmt.Errorf("start websocket client failed: %w", err)
+	}
+
+	events, err := wsClient.Subscribe(ctx, subscriber, query, 100)
+	if err != nil {
+		_ = wsClient.Stop()
+		return nil, fmt.Errorf("subscribe %s failed: %w", query, err)
+	}
+
+	heads := make(chan int64, 1)
+	go func() {
+		defer close(heads)
+		defer func() {
+			_ = wsClient.Stop()
+		}()
+
+		timer := time.NewTimer(clSubscriptionTimeout)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				log.Warn().Str("subscriber", subscriber).Str("query", query).Msg("no event received, subscription dropped")
+				return
+			case ev := <-events:
+				timer.Reset(clSubscriptionTimeout)
+
+				var height int64
+				switch data := ev.Data.(type) {
+				case types.EventDataNewBlock:
+					height = data.Block.Height
+				case types.EventDataNewBlockEvents:
+					height = data.Height
+				default:
+					continue
+				}
+
+				// Replace the pending height if the receiver has not consumed it yet.
+				select {
+				case <-heads:
+				default:
+				}
+				heads <- height
+			}
+		}
+	}()
+
+	return heads, nil
+}
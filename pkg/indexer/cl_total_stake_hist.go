@@ -7,33 +7,64 @@ import (
 	"strconv"
 	"time"
 
+	comethttp "github.com/cometbft/cometbft/rpc/client/http"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/chain"
+	"github.com/piplabs/story-staking-api/pkg/metrics"
 )
 
-const (
-	genesisStakeAmount = 8000000 // gwei
+var _ DependentIndexer = (*CLTotalStakeHistIndexer)(nil)
 
-	genesisBlockHeight = 1
-	genesisTime        = "2025-01-19T15:00:00.00000000Z"
-	timeLayout         = "2006-01-02T15:04:05.999999999Z"
-)
+// StakingPool is the staking pool as of a block height, in gwei.
+type StakingPool struct {
+	BondedTokens int64
+	// UnbondedValidatorTokens are the tokens of the validators not bonded, jailed or out of the active set. They
+	// are left out of the bonded tokens but remain staked.
+	UnbondedValidatorTokens int64
+}
 
-var _ Indexer = (*CLTotalStakeHistIndexer)(nil)
+// StakingPoolFetcher returns the staking pool as of the block height.
+type StakingPoolFetcher func(height int64) (*StakingPool, error)
 
+// CLTotalStakeHistIndexer keeps the running network total stake from the successful events changing the stake of
+// the validators, and periodically reconciles it with the bonded tokens of the staking pool, offset by the tokens of
+// the unbonded validators.
 type CLTotalStakeHistIndexer struct {
 	ctx context.Context
 
 	dbOperator *gorm.DB
+
+	cometClient *comethttp.HTTP
+
+	chain chain.Profile
+
+	fetchStakingPool  StakingPoolFetcher
+	reconcileInterval time.Duration
+	reconcileCorrect  bool
+	lastReconcileTime time.Time
 }
 
-func NewCLTotalStakeHistIndexer(ctx context.Context, dbOperator *gorm.DB) (*CLTotalStakeHistIndexer, error) {
+func NewCLTotalStakeHistIndexer(ctx context.Context, dbOperator *gorm.DB, rpcEndpoint string, conf Config, profile chain.Profile, fetchStakingPool StakingPoolFetcher) (*CLTotalStakeHistIndexer, error) {
+	cometClient, err := comethttp.New(rpcEndpoint, "")
+	if err != nil {
+		return nil, err
+	}
+
 	c := &CLTotalStakeHistIndexer{
 		ctx: ctx,
 
 		dbOperator: dbOperator,
+
+		cometClient: cometClient,
+
+		chain: profile,
+
+		fetchStakingPool:  fetchStakingPool,
+		reconcileInterval: conf.ReconcileInterval,
+		reconcileCorrect:  conf.ReconcileCorrect,
 	}
 
 	if err := c.init(); err != nil {
@@ -44,37 +75,24 @@ func NewCLTotalStakeHistIndexer(ctx context.Context, dbOperator *gorm.DB) (*CLTo
 }
 
 func (c *CLTotalStakeHistIndexer) Name() string {
-	return "cl_total_stake_hist"
+	return NameCLTotalStakeHist
 }
 
-func (c *CLTotalStakeHistIndexer) Run() {
-	log.Info().Str("indexer", c.Name()).Msg("Start indexing")
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+func (c *CLTotalStakeHistIndexer) ChainHead() (int64, error) {
+	return clChainHead(c.ctx, c.cometClient)
+}
 
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		case <-ticker.C:
-			if err := c.index(); err != nil {
-				log.Error().Err(err).Str("indexer", c.Name()).Msg("index cl total stake hist failed")
-			}
-		}
-	}
+// Dependencies returns the indexers providing the staking events, the slashes and their block time.
+func (c *CLTotalStakeHistIndexer) Dependencies() []string {
+	return []string{NameCLBlock, NameCLStakingEvent}
 }
 
+// init stores the genesis total stake of the chain profile.
 func (c *CLTotalStakeHistIndexer) init() error {
-	gt, err := time.Parse(timeLayout, genesisTime)
-	if err != nil {
-		return fmt.Errorf("parse genesis time failed: %w", err)
-	}
-
 	if err := db.UpsertCLGenesisTotalStakeHist(c.dbOperator, c.Name(), &db.CLTotalStakeHist{
-		TotalStakeAmount: genesisStakeAmount,
-		UpdatedAtBlock:   genesisBlockHeight,
-		UpdatedAtTime:    gt.Unix(),
+		TotalStakeAmount: c.chain.GenesisStakeAmount,
+		UpdatedAtBlock:   c.chain.GenesisBlockHeight,
+		UpdatedAtTime:    c.chain.GenesisTime.Unix(),
 	}); err != nil {
 		return fmt.Errorf("upsert genesis cl total stake failed: %w", err)
 	}
@@ -82,43 +100,57 @@ func (c *CLTotalStakeHistIndexer) init() error {
 	return nil
 }
 
-func (c *CLTotalStakeHistIndexer) index() error {
+func (c *CLTotalStakeHistIndexer) Index(from, to int64) error {
 	latestHist, err := db.GetLatestCLTotalStakeHist(c.dbOperator)
 	if err != nil {
 		return fmt.Errorf("get latest cl total stake hist failed: %w", err)
 	}
 
-	events, err := db.GetSuccessfulCLStakingEventsAfter(c.dbOperator, []string{TypeStake, TypeUnstake}, latestHist.UpdatedAtBlock)
+	// Blocks up to the latest history are already accounted for in its total stake amount.
+	from = max(from, latestHist.UpdatedAtBlock+1)
+
+	events, err := db.GetSuccessfulCLStakingEvents(c.dbOperator, []string{
+		TypeCreateValidator,
+		TypeStake, TypeStakeOnBehalf,
+		TypeUnstake, TypeUnstakeOnBehalf,
+	}, from, to)
 	if err != nil {
 		return fmt.Errorf("get successful cl staking events failed: %w", err)
-	} else if len(events) == 0 {
-		return nil
 	}
 
 	blk2StakeChange, blk2BlockTime := make(map[int64]int64), make(map[int64]int64)
 	for _, event := range events {
-		var amount int64
-		if event.Amount == "" {
-			// special bypass for aeneid
-			amount = 0
-		} else {
-			amt, err := strconv.ParseInt(event.Amount, 10, 64)
-			if err != nil {
-				return fmt.Errorf("parse amount %s failed: %w", event.Amount, err)
-			}
-			amount = amt
+		amount, err := stakingEventAmount(event.Amount, c.chain.Quirks.AllowEmptyStakeAmounts())
+		if err != nil {
+			return err
 		}
 
+		// Redelegations move stake between validators, the total is unchanged.
 		switch event.EventType {
-		case TypeStake:
+		case TypeCreateValidator, TypeStake, TypeStakeOnBehalf:
 			blk2StakeChange[event.BlockHeight] += amount
-		case TypeUnstake:
+		case TypeUnstake, TypeUnstakeOnBehalf:
 			blk2StakeChange[event.BlockHeight] -= amount
 		}
 
 		blk2BlockTime[event.BlockHeight] = event.BlockTime.Unix()
 	}
 
+	slashes, err := db.GetCLPenaltyAmounts(c.dbOperator, PenaltyKindSlash, from, to)
+	if err != nil {
+		return fmt.Errorf("get cl slashed amounts failed: %w", err)
+	}
+
+	for _, slash := range slashes {
+		amount, err := stakingEventAmount(slash.Amount, c.chain.Quirks.AllowEmptyStakeAmounts())
+		if err != nil {
+			return err
+		}
+
+		blk2StakeChange[slash.BlockHeight] -= amount
+		blk2BlockTime[slash.BlockHeight] = slash.BlockTime.Unix()
+	}
+
 	type stakeChange struct {
 		BlockHeight       int64
 		BlockTime         int64
@@ -149,5 +181,92 @@ func (c *CLTotalStakeHistIndexer) index() error {
 		})
 	}
 
-	return db.BatchUpsertCLTotalStakeHists(c.dbOperator, c.Name(), clTotalStakeHists)
+	var drift *db.CLTotalStakeDrift
+	if from <= to {
+		drift, err = c.reconcile(to, totalStakeAmount)
+		if err != nil {
+			return err
+		}
+	}
+
+	if drift != nil && drift.Corrected {
+		// The total stake is reset to the staked tokens from the block on.
+		stakedTokens := drift.BondedTokens + drift.UnbondedValidatorTokens
+		if n := len(clTotalStakeHists); n > 0 && clTotalStakeHists[n-1].UpdatedAtBlock == to {
+			clTotalStakeHists[n-1].TotalStakeAmount = stakedTokens
+		} else {
+			clTotalStakeHists = append(clTotalStakeHists, &db.CLTotalStakeHist{
+				TotalStakeAmount: stakedTokens,
+				UpdatedAtBlock:   to,
+				UpdatedAtTime:    drift.BlockTime,
+			})
+		}
+	}
+
+	return db.BatchUpsertCLTotalStakeHists(c.dbOperator, c.Name(), clTotalStakeHists, drift, to)
+}
+
+// reconcile compares the total stake as of the block with the bonded tokens of the staking pool plus the tokens of
+// the unbonded validators once the reconcile interval has passed since the last attempt. The drift is nil if they match or no reconciliation is due,
+// an unavailable staking pool only skips the reconciliation.
+func (c *CLTotalStakeHistIndexer) reconcile(height, totalStakeAmount int64) (*db.CLTotalStakeDrift, error) {
+	if c.reconcileInterval <= 0 || time.Since(c.lastReconcileTime) < c.reconcileInterval {
+		return nil, nil
+	}
+	c.lastReconcileTime = time.Now()
+
+	pool, err := c.fetchStakingPool(height)
+	if err != nil {
+		log.Warn().Err(err).Str("indexer", c.Name()).Int64("height", height).Msg("failed to get staking pool, skip reconciliation")
+		return nil, nil
+	}
+
+	driftAmount := pool.BondedTokens + pool.UnbondedValidatorTokens - totalStakeAmount
+	metrics.TotalStakeDrift.Set(float64(driftAmount))
+	if driftAmount == 0 {
+		return nil, nil
+	}
+
+	blks, err := db.GetCLBlocks(c.dbOperator, []int64{height})
+	if err != nil {
+		return nil, fmt.Errorf("get cl block failed: %w", err)
+	} else if len(blks) == 0 {
+		// Quarantined by the cl block indexer, the next reconciliation takes over.
+		log.Warn().Str("indexer", c.Name()).Int64("height", height).Msg("block not indexed by cl block indexer, skip reconciliation")
+		return nil, nil
+	}
+
+	log.Warn().
+		Str("indexer", c.Name()).
+		Int64("height", height).
+		Int64("total_stake_amount", totalStakeAmount).
+		Int64("bonded_tokens", pool.BondedTokens).
+		Int64("unbonded_validator_tokens", pool.UnbondedValidatorTokens).
+		Bool("corrected", c.reconcileCorrect).
+		Msg("total stake drifted from bonded tokens")
+
+	return &db.CLTotalStakeDrift{
+		BlockHeight:             height,
+		BlockTime:               blks[0].Time.Unix(),
+		TotalStakeAmount:        totalStakeAmount,
+		BondedTokens:            pool.BondedTokens,
+		UnbondedValidatorTokens: pool.UnbondedValidatorTokens,
+		Drift:                   driftAmount,
+		Corrected:               c.reconcileCorrect,
+	}, nil
+}
+
+// stakingEventAmount parses the amount of a staking event in gwei, an empty amount counts as zero on the networks
+// with the empty stake amounts quirk.
+func stakingEventAmount(amount string, allowEmpty bool) (int64, error) {
+	if amount == "" && allowEmpty {
+		return 0, nil
+	}
+
+	amt, err := strconv.ParseInt(amount, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse amount %s failed: %w", amount, err)
+	}
+
+	return amt, nil
 }
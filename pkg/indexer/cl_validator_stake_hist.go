@@ -0,0 +1,173 @@
+package indexer
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	comethttp "github.com/cometbft/cometbft/rpc/client/http"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/chain"
+)
+
+var _ DependentIndexer = (*CLValidatorStakeHistIndexer)(nil)
+
+// CLValidatorStakeHistIndexer keeps the running total stake of each validator from the successful create validator,
+// stake, unstake and redelegate events and the slashes, starting from the genesis validators of the chain profile.
+type CLValidatorStakeHistIndexer struct {
+	ctx context.Context
+
+	dbOperator *gorm.DB
+
+	cometClient *comethttp.HTTP
+
+	chain chain.Profile
+}
+
+func NewCLValidatorStakeHistIndexer(ctx context.Context, dbOperator *gorm.DB, rpcEndpoint string, profile chain.Profile) (*CLValidatorStakeHistIndexer, error) {
+	cometClient, err := comethttp.New(rpcEndpoint, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &CLValidatorStakeHistIndexer{
+		ctx: ctx,
+
+		dbOperator: dbOperator,
+
+		cometClient: cometClient,
+
+		chain: profile,
+	}, nil
+}
+
+func (c *CLValidatorStakeHistIndexer) Name() string {
+	return NameCLValidatorStakeHist
+}
+
+func (c *CLValidatorStakeHistIndexer) ChainHead() (int64, error) {
+	return clChainHead(c.ctx, c.cometClient)
+}
+
+// Dependencies returns the indexers providing the staking events, the slashes, their block time and the evm
+// addresses of the slashed validators. The el staking event indexer providing the validators of the events follows
+// the heights of the other layer, the events it has not indexed yet are retried by Index instead.
+func (c *CLValidatorStakeHistIndexer) Dependencies() []string {
+	return []string{NameCLBlock, NameCLStakingEvent, NameCLValidatorVote}
+}
+
+func (c *CLValidatorStakeHistIndexer) Index(from, to int64) error {
+	events, err := db.GetSuccessfulCLValidatorStakingEvents(c.dbOperator, []string{
+		TypeCreateValidator,
+		TypeStake, TypeStakeOnBehalf,
+		TypeUnstake, TypeUnstakeOnBehalf,
+		TypeRedelegate, TypeRedelegateOnBehalf,
+	}, from, to)
+	if err != nil {
+		return fmt.Errorf("get successful cl validator staking events failed: %w", err)
+	}
+
+	type stakeKey struct {
+		validator string
+		height    int64
+	}
+
+	stakeChanges, blk2BlockTime := make(map[stakeKey]int64), make(map[int64]int64)
+
+	// The genesis validators are bonded with the genesis block.
+	if genesisHeight := c.chain.GenesisBlockHeight; from <= genesisHeight && genesisHeight <= to {
+		for _, v := range c.chain.GenesisValidators {
+			stakeChanges[stakeKey{validator: v.Address, height: genesisHeight}] += v.StakeAmount
+		}
+		blk2BlockTime[genesisHeight] = c.chain.GenesisTime.Unix()
+	}
+
+	for _, event := range events {
+		// Retried until the el staking event indexer caught up, it lags behind as it waits for confirmations.
+		if event.DstValidatorAddress == "" {
+			return fmt.Errorf("el staking event of event %d of tx %s of block %d not indexed", event.EventIndex, event.ELTxHash, event.BlockHeight)
+		}
+
+		amount, err := stakingEventAmount(event.Amount, c.chain.Quirks.AllowEmptyStakeAmounts())
+		if err != nil {
+			return err
+		}
+
+		dst := stakeKey{validator: event.DstValidatorAddress, height: event.BlockHeight}
+		switch event.EventType {
+		case TypeCreateValidator, TypeStake, TypeStakeOnBehalf:
+			stakeChanges[dst] += amount
+		case TypeUnstake, TypeUnstakeOnBehalf:
+			stakeChanges[dst] -= amount
+		case TypeRedelegate, TypeRedelegateOnBehalf:
+			stakeChanges[stakeKey{validator: event.SrcValidatorAddress, height: event.BlockHeight}] -= amount
+			stakeChanges[dst] += amount
+		}
+
+		blk2BlockTime[event.BlockHeight] = event.BlockTime.Unix()
+	}
+
+	slashes, err := db.GetCLValidatorPenaltyAmounts(c.dbOperator, PenaltyKindSlash, from, to)
+	if err != nil {
+		return fmt.Errorf("get cl validator slashed amounts failed: %w", err)
+	}
+
+	for _, slash := range slashes {
+		if slash.Validator == "" {
+			return fmt.Errorf("validator %s slashed in block %d not indexed", slash.ConsensusAddress, slash.BlockHeight)
+		}
+
+		amount, err := stakingEventAmount(slash.Amount, c.chain.Quirks.AllowEmptyStakeAmounts())
+		if err != nil {
+			return err
+		}
+
+		stakeChanges[stakeKey{validator: slash.Validator, height: slash.BlockHeight}] -= amount
+		blk2BlockTime[slash.BlockHeight] = slash.BlockTime.Unix()
+	}
+
+	keys := make([]stakeKey, 0, len(stakeChanges))
+	validatorSet := make(map[string]struct{})
+	for key := range stakeChanges {
+		keys = append(keys, key)
+		validatorSet[key.validator] = struct{}{}
+	}
+
+	validators := make([]string, 0, len(validatorSet))
+	for validator := range validatorSet {
+		validators = append(validators, validator)
+	}
+
+	slices.SortFunc(keys, func(a, b stakeKey) int {
+		if c := cmp.Compare(a.height, b.height); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.validator, b.validator)
+	})
+
+	latestHists, err := db.GetLatestCLValidatorStakeHists(c.dbOperator, validators...)
+	if err != nil {
+		return fmt.Errorf("get latest cl validator stake hists failed: %w", err)
+	}
+
+	totalStakeAmounts := make(map[string]int64, len(latestHists))
+	for validator, hist := range latestHists {
+		totalStakeAmounts[validator] = hist.TotalStakeAmount
+	}
+
+	clValidatorStakeHists := make([]*db.CLValidatorStakeHist, 0, len(keys))
+	for _, key := range keys {
+		totalStakeAmounts[key.validator] += stakeChanges[key]
+		clValidatorStakeHists = append(clValidatorStakeHists, &db.CLValidatorStakeHist{
+			Validator:        key.validator,
+			TotalStakeAmount: totalStakeAmounts[key.validator],
+			UpdatedAtBlock:   key.height,
+			UpdatedAtTime:    blk2BlockTime[key.height],
+		})
+	}
+
+	return db.BatchUpsertCLValidatorStakeHists(c.dbOperator, c.Name(), clValidatorStakeHists, to)
+}
@@ -17,7 +17,7 @@ import (
 	"github.com/piplabs/story-staking-api/pkg/util"
 )
 
-var _ Indexer = (*CLValidatorVoteIndexer)(nil)
+var _ HeadSubscriber = (*CLValidatorVoteIndexer)(nil)
 
 type CLValidatorVoteIndexer struct {
 	ctx context.Context
@@ -25,10 +25,22 @@ type CLValidatorVoteIndexer struct {
 	dbOperator    *gorm.DB
 	cacheOperator *redis.Client
 
+	rpcEndpoint string
 	cometClient *comethttp.HTTP
+
+	fetchUptimeWindow UptimeWindowFetcher
+
+	// The streaks going on too short to be incidents yet, as of the pending height. They are only stored once
+	// long enough.
+	pendingStreaks map[string]*db.ValidatorIncident
+	pendingHeight  int64
 }
 
-func NewCLValidatorVoteIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string) (*CLValidatorVoteIndexer, error) {
+// UptimeWindowFetcher returns the number of the latest blocks the uptime of validators is measured over, it
+// follows the signed blocks window of the chain.
+type UptimeWindowFetcher func() (int64, error)
+
+func NewCLValidatorVoteIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, fetchUptimeWindow UptimeWindowFetcher) (*CLValidatorVoteIndexer, error) {
 	cometClient, err := comethttp.New(rpcEndpoint, "")
 	if err != nil {
 		return nil, err
@@ -40,90 +52,333 @@ func NewCLValidatorVoteIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOp
 		dbOperator:    dbOperator,
 		cacheOperator: cacheOperator,
 
+		rpcEndpoint: rpcEndpoint,
 		cometClient: cometClient,
+
+		fetchUptimeWindow: fetchUptimeWindow,
 	}, nil
 }
 
 func (c *CLValidatorVoteIndexer) Name() string {
-	return "cl_validator_vote"
+	return NameCLValidatorVote
 }
 
-func (c *CLValidatorVoteIndexer) Run() {
-	log.Info().Str("indexer", c.Name()).Msg("Start indexing")
+func (c *CLValidatorVoteIndexer) ChainHead() (int64, error) {
+	return clChainHead(c.ctx, c.cometClient)
+}
 
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+func (c *CLValidatorVoteIndexer) SubscribeHead(ctx context.Context) (<-chan int64, error) {
+	return subscribeCLHead(ctx, c.rpcEndpoint, c.Name(), types.EventQueryNewBlock.String())
+}
 
-	for {
-		select {
-		case <-c.ctx.Done():
-			return
-		case <-ticker.C:
-			indexPoint, err := db.GetIndexPoint(c.dbOperator, c.Name())
-			if err != nil {
-				log.Error().Err(err).Str("indexer", c.Name()).Msg("get index point failed")
-				continue
+func (c *CLValidatorVoteIndexer) Index(from, to int64) error {
+	validatorVotes := make([]*db.CLValidatorVote, 0)
+	// The active validators keyed by consensus address, as of the last block they are active in.
+	validatorsMap := make(map[string]*db.CLValidator)
+
+	type uptimeKey struct {
+		validator string
+		day       int64
+	}
+	uptimesMap := make(map[uptimeKey]*db.ValidatorUptimeDaily)
+
+	streaks, err := c.ongoingStreaks(from)
+	if err != nil {
+		return err
+	}
+
+	// The voting powers of the latest snapshot keyed by consensus address, a snapshot is taken when they change.
+	latestSet, err := db.GetLatestCLValidatorSet(c.dbOperator)
+	if err != nil {
+		return err
+	}
+	powers := make(map[string]int64, len(latestSet))
+	for _, v := range latestSet {
+		powers[v.ConsensusAddress] = v.VotingPower
+	}
+	snapshots := make([]*db.CLValidatorSetSnapshot, 0)
+
+	for i := from; i <= to; i++ {
+		blkVotes, err := c.fetchValidatorVotes(c.ctx, i)
+		if err != nil {
+			return err
+		}
+
+		validatorVotes = append(validatorVotes, blkVotes.votes...)
+
+		signed := make(map[string]bool, len(blkVotes.votes))
+		for _, v := range blkVotes.votes {
+			signed[v.Validator] = true
+		}
+
+		if validatorSetChanged(powers, blkVotes.validators) {
+			snapshots = append(snapshots, blkVotes.validators...)
+
+			powers = make(map[string]int64, len(blkVotes.validators))
+			for _, v := range blkVotes.validators {
+				powers[v.ConsensusAddress] = v.VotingPower
 			}
+		}
 
-			latestBlk, err := c.cometClient.Block(c.ctx, nil)
-			if err != nil {
-				log.Error().Err(err).Str("indexer", c.Name()).Msg("get latest cl block failed")
-				continue
+		active := make(map[string]bool, len(blkVotes.validators))
+		day := blkVotes.time.UTC().Truncate(24 * time.Hour).Unix()
+		for _, v := range blkVotes.validators {
+			validatorsMap[v.ConsensusAddress] = &db.CLValidator{
+				ConsensusAddress: v.ConsensusAddress,
+				EVMAddress:       v.EVMAddress,
+				VotingPower:      v.VotingPower,
+				LastActiveHeight: i,
 			}
+			active[v.EVMAddress] = true
 
-			from, to := indexPoint.BlockHeight+1, latestBlk.Block.Height-IndexLag
+			key := uptimeKey{validator: v.EVMAddress, day: day}
+			uptime, ok := uptimesMap[key]
+			if !ok {
+				uptime = &db.ValidatorUptimeDaily{Validator: v.EVMAddress, Day: day}
+				uptimesMap[key] = uptime
+			}
 
-			if from+10 > to {
+			uptime.TotalBlocks++
+			if signed[v.EVMAddress] {
+				uptime.SignedBlocks++
 				continue
 			}
+			uptime.MissedBlocks++
 
-			if err := c.index(from, to); err != nil {
-				log.Error().Err(err).
-					Str("indexer", c.Name()).
-					Int64("from", from).
-					Int64("to", to).
-					Msg("index cl validator votes failed")
-			}
+			streaks.miss(v.EVMAddress, i, blkVotes.time)
+		}
+
+		streaks.end(active, signed)
+	}
+
+	validators := make([]*db.CLValidator, 0, len(validatorsMap))
+	for _, v := range validatorsMap {
+		validators = append(validators, v)
+	}
+
+	uptimes := make([]*db.ValidatorUptimeDaily, 0, len(uptimesMap))
+	for _, uptime := range uptimesMap {
+		uptimes = append(uptimes, uptime)
+	}
+
+	// The votes out of a shrunk window are pruned with the batch, the ones of a grown window are only kept from
+	// then on.
+	uptimeWindow, err := c.fetchUptimeWindow()
+	if err != nil {
+		return fmt.Errorf("get uptime window failed: %w", err)
+	}
+
+	if err := db.BatchUpdateCLValidatorVotes(c.dbOperator, c.Name(), &db.CLValidatorVoteBatch{
+		Votes:      validatorVotes,
+		Validators: validators,
+		Uptimes:    uptimes,
+		Incidents:  streaks.incidents(),
+		Snapshots:  snapshots,
+
+		UptimeWindow: uptimeWindow,
+	}, to); err != nil {
+		return err
+	}
+
+	c.pendingStreaks, c.pendingHeight = streaks.pending(), to
+
+	c.invalidateCache()
+
+	return nil
+}
+
+// ongoingStreaks returns the streaks going on before the block, the stored incidents along with the short streaks
+// kept since the previous range. The short streaks are restored from the stored votes after a restart or when
+// another instance indexed the previous range.
+func (c *CLValidatorVoteIndexer) ongoingStreaks(from int64) (*missedStreaks, error) {
+	ongoing, err := db.GetOngoingValidatorIncidents(c.dbOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	streaks := newMissedStreaks()
+	for _, incident := range ongoing {
+		streaks.ongoing[incident.Validator] = incident
+	}
+
+	if c.pendingStreaks != nil && c.pendingHeight == from-1 {
+		// Copied, the range may fail and be indexed again.
+		for valAddr, streak := range c.pendingStreaks {
+			pending := *streak
+			streaks.ongoing[valAddr] = &pending
 		}
+
+		return streaks, nil
 	}
+
+	if err := c.restorePendingStreaks(streaks, from); err != nil {
+		return nil, fmt.Errorf("restore missed-block streaks failed: %w", err)
+	}
+
+	return streaks, nil
 }
 
-func (c *CLValidatorVoteIndexer) index(from, to int64) error {
-	start := from
+// restorePendingStreaks adds the short streaks going on before the block, a validator of the latest set whose last
+// vote is less than util.IncidentMinMissedBlocks blocks before has been missing the blocks since.
+func (c *CLValidatorVoteIndexer) restorePendingStreaks(streaks *missedStreaks, from int64) error {
+	latestSet, err := db.GetLatestCLValidatorSet(c.dbOperator)
+	if err != nil {
+		return err
+	}
+
+	validators := make([]string, 0, len(latestSet))
+	for _, v := range latestSet {
+		if _, ok := streaks.ongoing[v.EVMAddress]; !ok {
+			validators = append(validators, v.EVMAddress)
+		}
+	}
 
-	for start <= to {
-		end := min(start+100, to)
+	lastVotes, err := db.GetLastCLValidatorVotes(c.dbOperator, validators, from)
+	if err != nil {
+		return err
+	}
+
+	endHeight := from - 1
+	var endTime time.Time
+	for valAddr, lastVote := range lastVotes {
+		blockCount := endHeight - lastVote
+		if blockCount <= 0 || blockCount >= util.IncidentMinMissedBlocks {
+			continue
+		}
+
+		startHeight := lastVote + 1
+		startRes, err := c.cometClient.Header(c.ctx, &startHeight)
+		if err != nil {
+			return err
+		}
 
-		validatorVotes := make([]*db.CLValidatorVote, 0)
-		for i := start; i <= end; i++ {
-			valVotes, err := c.fetchValidatorVotes(c.ctx, i)
+		if endTime.IsZero() {
+			endRes, err := c.cometClient.Header(c.ctx, &endHeight)
 			if err != nil {
 				return err
 			}
+			endTime = endRes.Header.Time
+		}
 
-			validatorVotes = append(validatorVotes, valVotes...)
+		streaks.ongoing[valAddr] = &db.ValidatorIncident{
+			Validator:   valAddr,
+			StartHeight: startHeight,
+			EndHeight:   endHeight,
+			BlockCount:  blockCount,
+			StartTime:   startRes.Header.Time,
+			EndTime:     endTime,
+			Ongoing:     true,
 		}
+	}
 
-		if err := db.BatchUpdateCLValidatorVotes(c.dbOperator, c.Name(), validatorVotes, end); err != nil {
-			return err
+	return nil
+}
+
+// missedStreaks tracks the streaks of consecutive blocks missed by the validators of the active set over a range.
+type missedStreaks struct {
+	ongoing map[string]*db.ValidatorIncident // Keyed by validator
+	touched map[*db.ValidatorIncident]struct{}
+}
+
+func newMissedStreaks() *missedStreaks {
+	return &missedStreaks{
+		ongoing: make(map[string]*db.ValidatorIncident),
+		touched: make(map[*db.ValidatorIncident]struct{}),
+	}
+}
+
+// miss extends the streak of the validator with the block, or starts one.
+func (m *missedStreaks) miss(valAddr string, height int64, blockTime time.Time) {
+	streak, ok := m.ongoing[valAddr]
+	if !ok {
+		streak = &db.ValidatorIncident{
+			Validator:   valAddr,
+			StartHeight: height,
+			StartTime:   blockTime,
+			Ongoing:     true,
+		}
+		m.ongoing[valAddr] = streak
+	}
+	streak.EndHeight = height
+	streak.EndTime = blockTime
+	streak.BlockCount++
+	m.touched[streak] = struct{}{}
+}
+
+// end ends the streaks of the validators that signed the block or left the active set.
+func (m *missedStreaks) end(active, signed map[string]bool) {
+	for valAddr, streak := range m.ongoing {
+		if active[valAddr] && !signed[valAddr] {
+			continue
 		}
 
-		start = end + 1
+		streak.Ongoing = false
+		m.touched[streak] = struct{}{}
+		delete(m.ongoing, valAddr)
 	}
+}
 
-	c.invalidateCache()
+// incidents returns the streaks started or updated over the range that are long enough to be incidents.
+func (m *missedStreaks) incidents() []*db.ValidatorIncident {
+	incidents := make([]*db.ValidatorIncident, 0, len(m.touched))
+	for streak := range m.touched {
+		if streak.BlockCount >= util.IncidentMinMissedBlocks {
+			incidents = append(incidents, streak)
+		}
+	}
 
-	return nil
+	return incidents
+}
+
+// pending returns the streaks going on too short to be incidents yet.
+func (m *missedStreaks) pending() map[string]*db.ValidatorIncident {
+	pending := make(map[string]*db.ValidatorIncident)
+	for valAddr, streak := range m.ongoing {
+		if streak.BlockCount < util.IncidentMinMissedBlocks {
+			pending[valAddr] = streak
+		}
+	}
+
+	return pending
+}
+
+// validatorSetChanged reports whether the active set differs from the voting powers keyed by consensus address,
+// in its members or their voting powers.
+func validatorSetChanged(powers map[string]int64, validators []*db.CLValidatorSetSnapshot) bool {
+	if len(powers) != len(validators) {
+		return true
+	}
+
+	for _, v := range validators {
+		if power, ok := powers[v.ConsensusAddress]; !ok || power != v.VotingPower {
+			return true
+		}
+	}
+
+	return false
+}
+
+// blockVotes is the votes of a block along with its active validators, the active validators without a vote
+// missed the block.
+type blockVotes struct {
+	votes      []*db.CLValidatorVote
+	validators []*db.CLValidatorSetSnapshot
+	time       time.Time
 }
 
-func (c *CLValidatorVoteIndexer) fetchValidatorVotes(ctx context.Context, height int64) ([]*db.CLValidatorVote, error) {
+func (c *CLValidatorVoteIndexer) fetchValidatorVotes(ctx context.Context, height int64) (*blockVotes, error) {
 	validatorVotes := make([]*db.CLValidatorVote, 0)
 
-	cometAddrToEVMAddr, err := c.fetchActiveValidators(ctx, height)
+	activeValidators, err := c.fetchActiveValidators(ctx, height)
 	if err != nil {
 		return nil, err
 	}
 
+	cometAddrToEVMAddr := make(map[string]string, len(activeValidators))
+	for _, v := range activeValidators {
+		cometAddrToEVMAddr[v.ConsensusAddress] = v.EVMAddress
+	}
+
 	commitRes, err := c.cometClient.Commit(ctx, &height)
 	if err != nil {
 		return nil, err
@@ -138,6 +393,11 @@ func (c *CLValidatorVoteIndexer) fetchValidatorVotes(ctx context.Context, height
 	}
 
 	for _, sig := range commitRes.Commit.Signatures {
+		// The validator missed the block.
+		if sig.BlockIDFlag == types.BlockIDFlagAbsent {
+			continue
+		}
+
 		if !(sig.BlockIDFlag == types.BlockIDFlagCommit || sig.BlockIDFlag == types.BlockIDFlagNil) {
 			log.Warn().
 				Int64("height", height).
@@ -148,22 +408,33 @@ func (c *CLValidatorVoteIndexer) fetchValidatorVotes(ctx context.Context, height
 
 		cometAddr := sig.ValidatorAddress.String()
 
+		// Blocks are never skipped, a gap would corrupt the uptime rollups and the missed-block streaks.
 		evmAddr, ok := cometAddrToEVMAddr[cometAddr]
 		if !ok {
-			return nil, fmt.Errorf("validator %s in block %d not found in active validators", cometAddr, height)
+			return nil, fmt.Errorf("validator %s of block %d not found in active validators", cometAddr, height)
 		}
 
 		validatorVotes = append(validatorVotes, &db.CLValidatorVote{
-			Validator:   strings.ToLower(evmAddr),
+			Validator:   evmAddr,
 			BlockHeight: height,
 		})
 	}
 
-	return validatorVotes, nil
+	for _, v := range activeValidators {
+		v.BlockTime = commitRes.Header.Time
+	}
+
+	return &blockVotes{
+		votes:      validatorVotes,
+		validators: activeValidators,
+		time:       commitRes.Header.Time,
+	}, nil
 }
 
-func (c *CLValidatorVoteIndexer) fetchActiveValidators(ctx context.Context, height int64) (map[string]string, error) {
-	cometAddrToEVMAddr := make(map[string]string)
+// fetchActiveValidators returns the active validators of the block with their voting power and proposer priority,
+// mapping the consensus addresses to the EVM addresses. The block time is left to the caller.
+func (c *CLValidatorVoteIndexer) fetchActiveValidators(ctx context.Context, height int64) ([]*db.CLValidatorSetSnapshot, error) {
+	validators := make([]*db.CLValidatorSetSnapshot, 0)
 
 	page, perPage := 1, 100
 	for {
@@ -177,10 +448,16 @@ func (c *CLValidatorVoteIndexer) fetchActiveValidators(ctx context.Context, heig
 
 			evmAddr, err := util.CmpPubKeyToEVMAddress(validator.PubKey.Bytes())
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("derive evm address of validator %s of block %d failed: %w", cometAddr, height, err)
 			}
 
-			cometAddrToEVMAddr[cometAddr] = evmAddr.String()
+			validators = append(validators, &db.CLValidatorSetSnapshot{
+				Height:           height,
+				ConsensusAddress: cometAddr,
+				EVMAddress:       strings.ToLower(evmAddr.String()),
+				VotingPower:      validator.VotingPower,
+				ProposerPriority: validator.ProposerPriority,
+			})
 		}
 
 		if page*perPage >= validatorsRes.Total {
@@ -189,7 +466,7 @@ func (c *CLValidatorVoteIndexer) fetchActiveValidators(ctx context.Context, heig
 		page++
 	}
 
-	return cometAddrToEVMAddr, nil
+	return validators, nil
 }
 
 func (c *CLValidatorVoteIndexer) invalidateCache() {
@@ -0,0 +1,55 @@
+package indexer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/util"
+)
+
+func TestMissedStreaks(t *testing.T) {
+	streaks := newMissedStreaks()
+	// Stored as an incident in a previous range.
+	streaks.ongoing["0xval1"] = &db.ValidatorIncident{ID: 1, Validator: "0xval1", StartHeight: 1, EndHeight: 10, BlockCount: 10, Ongoing: true}
+
+	active := map[string]bool{"0xval1": true, "0xval2": true, "0xval3": true}
+	for height := int64(11); height < 11+util.IncidentMinMissedBlocks; height++ {
+		signed := map[string]bool{"0xval3": true}
+		// A short streak of 0xval3, ended by the next block it signs.
+		if height == 12 || height == 13 {
+			signed = map[string]bool{}
+			streaks.miss("0xval3", height, time.Unix(height, 0))
+		}
+
+		streaks.miss("0xval1", height, time.Unix(height, 0))
+		// 0xval2 misses all the blocks but the first one of the range.
+		if height > 11 {
+			streaks.miss("0xval2", height, time.Unix(height, 0))
+		} else {
+			signed["0xval2"] = true
+		}
+		streaks.end(active, signed)
+	}
+
+	// The short streaks are not incidents, ended or not.
+	incidents := streaks.incidents()
+	require.Equal(t, 1, len(incidents))
+	require.Equal(t, "0xval1", incidents[0].Validator)
+	require.Equal(t, int64(20), incidents[0].BlockCount)
+	require.True(t, incidents[0].Ongoing)
+
+	pending := streaks.pending()
+	require.Equal(t, 1, len(pending))
+	require.Equal(t, int64(12), pending["0xval2"].StartHeight)
+	require.Equal(t, int64(util.IncidentMinMissedBlocks-1), pending["0xval2"].BlockCount)
+
+	// Leaving the active set ends the streak, too short to be stored.
+	streaks.end(map[string]bool{"0xval1": true}, map[string]bool{})
+	incidents = streaks.incidents()
+	require.Equal(t, 1, len(incidents))
+	require.Equal(t, "0xval1", incidents[0].Validator)
+	require.Empty(t, streaks.pending())
+}
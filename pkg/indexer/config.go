@@ -0,0 +1,100 @@
+package indexer
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultInterval  = 10 * time.Second
+	DefaultBatchSize = 100
+
+	DefaultFetchConcurrency = 4
+)
+
+// Config is the configuration of a single indexer, set in the `[indexers.<name>]` section.
+type Config struct {
+	// Enabled defaults to true.
+	Enabled *bool `toml:"enabled"`
+	// Interval is the polling interval, defaults to DefaultInterval.
+	Interval time.Duration `toml:"interval"`
+	// Confirmations is the number of blocks the indexer stays behind the chain head, defaults to IndexLag.
+	Confirmations *int64 `toml:"confirmations"`
+	// BatchSize is the number of blocks committed in a single transaction, defaults to DefaultBatchSize.
+	BatchSize int64 `toml:"batch_size"`
+	// StartHeight is the first block height to index.
+	StartHeight int64 `toml:"start_height"`
+	// FetchConcurrency is the maximum number of blocks fetched from the node in parallel, defaults to DefaultFetchConcurrency.
+	// Only used by indexers fetching blocks one by one.
+	FetchConcurrency int `toml:"fetch_concurrency"`
+	// MaxRetries is the number of times a block failing with a BlockError is retried before it is quarantined
+	// and skipped. Zero, the default, never quarantines and retries forever.
+	MaxRetries int `toml:"max_retries"`
+	// Subscribe enables indexing on new block events pushed over websocket, polling is kept as a fallback.
+	// Only supported by indexers implementing HeadSubscriber.
+	Subscribe bool `toml:"subscribe"`
+	// ReconcileInterval is the minimum time between two reconciliations of the running total stake with the
+	// bonded tokens of the staking pool, zero disables them. Only used by the cl_total_stake_hist indexer.
+	ReconcileInterval time.Duration `toml:"reconcile_interval"`
+	// ReconcileCorrect resets the running total stake to the bonded tokens, offset by the unbonded validators, when
+	// a reconciliation finds a drift. Only used by the cl_total_stake_hist indexer.
+	ReconcileCorrect bool `toml:"reconcile_correct"`
+}
+
+func (c Config) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// WithDefaults returns a copy of the config with the unset fields filled with the defaults.
+func (c Config) WithDefaults() Config {
+	if c.Interval == 0 {
+		c.Interval = DefaultInterval
+	}
+
+	if c.Confirmations == nil {
+		confirmations := int64(IndexLag)
+		c.Confirmations = &confirmations
+	}
+
+	if c.BatchSize == 0 {
+		c.BatchSize = DefaultBatchSize
+	}
+
+	if c.FetchConcurrency == 0 {
+		c.FetchConcurrency = DefaultFetchConcurrency
+	}
+
+	return c
+}
+
+func (c Config) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("invalid interval: %s", c.Interval)
+	}
+
+	if c.Confirmations != nil && *c.Confirmations < 0 {
+		return fmt.Errorf("invalid confirmations: %d", *c.Confirmations)
+	}
+
+	if c.BatchSize < 0 {
+		return fmt.Errorf("invalid batch size: %d", c.BatchSize)
+	}
+
+	if c.FetchConcurrency < 0 {
+		return fmt.Errorf("invalid fetch concurrency: %d", c.FetchConcurrency)
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("invalid max retries: %d", c.MaxRetries)
+	}
+
+	if c.ReconcileInterval < 0 {
+		return fmt.Errorf("invalid reconcile interval: %s", c.ReconcileInterval)
+	}
+
+	if c.StartHeight < 0 {
+		return fmt.Errorf("invalid start height: %d", c.StartHeight)
+	}
+
+	return nil
+}
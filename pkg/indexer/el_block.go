@@ -2,18 +2,24 @@ package indexer
 
 import (
 	"context"
+	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	redis "github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
+	"github.com/piplabs/story-staking-api/cache"
 	"github.com/piplabs/story-staking-api/db"
 )
 
-var _ Indexer = (*ELBlockIndexer)(nil)
+var _ Reindexer = (*ELBlockIndexer)(nil)
+
+// elIndexers are the indexers whose data is derived from EL blocks, they are rolled back together on reorg.
+var elIndexers = []string{NameELBlock, NameELReward, NameELStakingEvent, NameELContractParam, NameELValidator, NameELWithdrawal}
 
 type ELBlockIndexer struct {
 	ctx context.Context
@@ -22,9 +28,11 @@ type ELBlockIndexer struct {
 	cacheOperator *redis.Client
 
 	ethClient *ethclient.Client
+
+	fetchConcurrency int
 }
 
-func NewELBlockIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string) (*ELBlockIndexer, error) {
+func NewELBlockIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, fetchConcurrency int) (*ELBlockIndexer, error) {
 	ethClient, err := ethclient.Dial(rpcEndpoint)
 	if err != nil {
 		return nil, err
@@ -37,81 +45,143 @@ func NewELBlockIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *
 		cacheOperator: cacheOperator,
 
 		ethClient: ethClient,
+
+		fetchConcurrency: fetchConcurrency,
 	}, nil
 }
 
 func (e *ELBlockIndexer) Name() string {
-	return "el_block"
+	return NameELBlock
 }
 
-func (e *ELBlockIndexer) Run() {
-	log.Info().Str("indexer", e.Name()).Msg("Start indexing")
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-ticker.C:
-			indexPoint, err := db.GetIndexPoint(e.dbOperator, e.Name())
-			if err != nil {
-				log.Error().Err(err).Str("indexer", e.Name()).Msg("get index point failed")
-				continue
-			}
-
-			latestBlkNum, err := e.ethClient.BlockNumber(e.ctx)
-			if err != nil {
-				log.Error().Err(err).Str("indexer", e.Name()).Msg("get latest el block failed")
-				continue
-			}
-
-			if indexPoint.BlockHeight+10 > int64(latestBlkNum) {
-				continue
-			}
-
-			if err := e.index(indexPoint.BlockHeight+1, int64(latestBlkNum)); err != nil {
-				log.Error().Err(err).
-					Str("indexer", e.Name()).
-					Int64("from", indexPoint.BlockHeight+1).
-					Uint64("to", latestBlkNum).
-					Msg("index el block failed")
-			}
-		}
+func (e *ELBlockIndexer) ChainHead() (int64, error) {
+	return elChainHead(e.ctx, e.ethClient)
+}
+
+func (e *ELBlockIndexer) Index(from, to int64) error {
+	if reorged, err := e.checkReorg(from - 1); err != nil {
+		return fmt.Errorf("check el reorg failed: %w", err)
+	} else if reorged {
+		return ErrReorgDetected
 	}
+
+	elBlocks, err := e.getBlocks(from, to)
+	if err != nil {
+		return err
+	}
+
+	return db.BatchCreateELBlocks(e.dbOperator, e.Name(), elBlocks, to)
 }
 
-func (e *ELBlockIndexer) index(from, to int64) error {
-	var elBlocks []*db.ELBlock
+func (e *ELBlockIndexer) Reindex(from, to int64) error {
+	elBlocks, err := e.getBlocks(from, to)
+	if err != nil {
+		return err
+	}
 
-	for i := from; i <= to; i++ {
-		blk, err := e.ethClient.BlockByNumber(e.ctx, big.NewInt(i))
-		if err != nil {
-			return err
+	return db.ReplaceELBlocks(e.dbOperator, from, to, elBlocks)
+}
+
+// getBlocks fetches the blocks in [from, to] and checks that they are chained to the stored parent block.
+func (e *ELBlockIndexer) getBlocks(from, to int64) ([]*db.ELBlock, error) {
+	blks, err := fetchBlocks(e.ctx, from, to, e.fetchConcurrency, func(ctx context.Context, height int64) (*types.Block, error) {
+		return e.ethClient.BlockByNumber(ctx, big.NewInt(height))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	elBlocks := make([]*db.ELBlock, 0, len(blks))
+
+	var parentHash string
+	if parent, err := db.GetELBlock(e.dbOperator, from-1); err != nil {
+		return nil, err
+	} else if parent != nil {
+		parentHash = parent.Hash
+	}
+
+	for _, blk := range blks {
+		// The chain reorganized while indexing, the next round will roll back to the fork point.
+		if parentHash != "" && blk.ParentHash().String() != parentHash {
+			return nil, fmt.Errorf("%w: parent hash of block %d mismatch", ErrReorgDetected, blk.Number().Int64())
 		}
+		parentHash = blk.Hash().String()
 
 		elBlocks = append(elBlocks, &db.ELBlock{
-			Height:   i,
-			Hash:     blk.Hash().String(),
-			GasUsed:  blk.GasUsed(),
-			GasLimit: blk.GasLimit(),
-			Time:     time.Unix(int64(blk.Time()), 0),
+			Height:     blk.Number().Int64(),
+			Hash:       blk.Hash().String(),
+			ParentHash: blk.ParentHash().String(),
+			GasUsed:    blk.GasUsed(),
+			GasLimit:   blk.GasLimit(),
+			Time:       time.Unix(int64(blk.Time()), 0),
 		})
+	}
+
+	return elBlocks, nil
+}
+
+// checkReorg checks whether the last indexed block is still canonical. If not, it finds the fork
+// point and rolls back all EL indexers to it. It reports whether a rollback happened.
+func (e *ELBlockIndexer) checkReorg(height int64) (bool, error) {
+	canonical, err := e.isCanonical(height)
+	if err != nil {
+		return false, err
+	} else if canonical {
+		return false, nil
+	}
 
-		if len(elBlocks) > 100 {
-			if err := db.BatchCreateELBlocks(e.dbOperator, e.Name(), elBlocks, i); err != nil {
-				return err
-			}
+	forkHeight := int64(-1)
+	for h := height - 1; h >= max(height-MaxReorgDepth, 0); h-- {
+		canonical, err := e.isCanonical(h)
+		if err != nil {
+			return false, err
+		}
 
-			elBlocks = make([]*db.ELBlock, 0)
+		if canonical {
+			forkHeight = h
+			break
 		}
 	}
 
-	// Handle remaining entries, even if there are no entries, we also need to update the index point.
-	if err := db.BatchCreateELBlocks(e.dbOperator, e.Name(), elBlocks, to); err != nil {
-		return err
+	if forkHeight < 0 {
+		return false, fmt.Errorf("fork point not found within %d blocks below %d", MaxReorgDepth, height)
+	}
+
+	log.Warn().
+		Str("indexer", e.Name()).
+		Int64("height", height).
+		Int64("fork_height", forkHeight).
+		Msg("el reorg detected, rolling back")
+
+	rewardAddrs, err := db.RollbackELBlocks(e.dbOperator, forkHeight, elIndexers)
+	if err != nil {
+		return false, fmt.Errorf("rollback el blocks to %d failed: %w", forkHeight, err)
+	}
+
+	for _, addr := range rewardAddrs {
+		_ = cache.InvalidateRedisData(e.ctx, e.cacheOperator, cache.RewardsKey(addr))
+		for _, kind := range WithdrawalKinds {
+			_ = cache.InvalidateRedisData(e.ctx, e.cacheOperator, cache.WithdrawalsKey(kind, addr))
+		}
+	}
+
+	return true, nil
+}
+
+// isCanonical reports whether the block stored at the given height matches the one on the node.
+// Heights that have not been stored are considered canonical.
+func (e *ELBlockIndexer) isCanonical(height int64) (bool, error) {
+	elBlk, err := db.GetELBlock(e.dbOperator, height)
+	if err != nil {
+		return false, err
+	} else if elBlk == nil {
+		return true, nil
+	}
+
+	header, err := e.ethClient.HeaderByNumber(e.ctx, big.NewInt(height))
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	return header.Hash().String() == elBlk.Hash, nil
 }
@@ -0,0 +1,333 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	redis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer/contract/iptokenstaking"
+)
+
+var (
+	_ DependentIndexer = (*ELContractParamIndexer)(nil)
+	_ Reindexer        = (*ELContractParamIndexer)(nil)
+)
+
+// Parameters of the staking contract.
+const (
+	ContractParamFee               = "fee"
+	ContractParamMinStakeAmount    = "min_stake_amount"
+	ContractParamMinUnstakeAmount  = "min_unstake_amount"
+	ContractParamMinCommissionRate = "min_commission_rate"
+	ContractParamOwner             = "owner"
+	ContractParamPendingOwner      = "pending_owner"
+)
+
+var ContractParams = []string{
+	ContractParamFee,
+	ContractParamMinStakeAmount,
+	ContractParamMinUnstakeAmount,
+	ContractParamMinCommissionRate,
+	ContractParamOwner,
+	ContractParamPendingOwner,
+}
+
+// Names of the staking contract events that change a parameter.
+const (
+	EventFeeSet                   = "FeeSet"
+	EventMinStakeAmountSet        = "MinStakeAmountSet"
+	EventMinUnstakeAmountSet      = "MinUnstakeAmountSet"
+	EventMinCommissionRateChanged = "MinCommissionRateChanged"
+	EventOwnershipTransferStarted = "OwnershipTransferStarted"
+	EventOwnershipTransferred     = "OwnershipTransferred"
+)
+
+var contractParamEventNames = []string{
+	EventFeeSet,
+	EventMinStakeAmountSet,
+	EventMinUnstakeAmountSet,
+	EventMinCommissionRateChanged,
+	EventOwnershipTransferStarted,
+	EventOwnershipTransferred,
+}
+
+type ELContractParamIndexer struct {
+	ctx context.Context
+
+	dbOperator    *gorm.DB
+	cacheOperator *redis.Client
+
+	ethClient       *ethclient.Client
+	contractAddress common.Address
+	elEventFilter   *iptokenstaking.IPTokenStakingFilterer
+	elCaller        *iptokenstaking.IPTokenStakingCaller
+
+	eventTopics      []common.Hash
+	eventTopics2Name map[common.Hash]string
+}
+
+func NewELContractParamIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, contractAddress common.Address) (*ELContractParamIndexer, error) {
+	ethClient, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	elEventFilter, err := iptokenstaking.NewIPTokenStakingFilterer(contractAddress, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	elCaller, err := iptokenstaking.NewIPTokenStakingCaller(contractAddress, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	contractABI, err := iptokenstaking.IPTokenStakingMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	eventTopics := make([]common.Hash, 0, len(contractParamEventNames))
+	eventTopics2Name := make(map[common.Hash]string, len(contractParamEventNames))
+	for _, name := range contractParamEventNames {
+		event, ok := contractABI.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("event %s not found in staking contract abi", name)
+		}
+
+		eventTopics = append(eventTopics, event.ID)
+		eventTopics2Name[event.ID] = name
+	}
+
+	return &ELContractParamIndexer{
+		ctx: ctx,
+
+		dbOperator:    dbOperator,
+		cacheOperator: cacheOperator,
+
+		ethClient:       ethClient,
+		contractAddress: contractAddress,
+		elEventFilter:   elEventFilter,
+		elCaller:        elCaller,
+
+		eventTopics:      eventTopics,
+		eventTopics2Name: eventTopics2Name,
+	}, nil
+}
+
+func (e *ELContractParamIndexer) Name() string {
+	return NameELContractParam
+}
+
+func (e *ELContractParamIndexer) ChainHead() (int64, error) {
+	return elChainHead(e.ctx, e.ethClient)
+}
+
+// Dependencies returns the el block indexer, only blocks that have been checked for reorg are indexed.
+func (e *ELContractParamIndexer) Dependencies() []string {
+	return []string{NameELBlock}
+}
+
+func (e *ELContractParamIndexer) Index(from, to int64) error {
+	hists, err := e.getParamHists(from, to)
+	if err != nil {
+		return err
+	}
+
+	// Parameters that never changed have no event, record the values the indexing starts from.
+	if hasHists, err := db.HasELContractParamHists(e.dbOperator); err != nil {
+		return err
+	} else if !hasHists && from > 0 {
+		// Retried with the batch until read, the node must keep the state of the block before the first one.
+		initHists, err := e.getInitialParams(from - 1)
+		if err != nil {
+			return fmt.Errorf("read initial contract params at block %d failed: %w", from-1, err)
+		}
+
+		hists = append(initHists, hists...)
+	}
+
+	return db.BatchCreateELContractParamHists(e.dbOperator, e.Name(), hists, to)
+}
+
+func (e *ELContractParamIndexer) Reindex(from, to int64) error {
+	hists, err := e.getParamHists(from, to)
+	if err != nil {
+		return err
+	}
+
+	return db.ReplaceELContractParamHists(e.dbOperator, from, to, hists)
+}
+
+func (e *ELContractParamIndexer) getParamHists(from, to int64) ([]*db.ELContractParamHist, error) {
+	logs, err := e.ethClient.FilterLogs(e.ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(from),
+		ToBlock:   big.NewInt(to),
+		Addresses: []common.Address{e.contractAddress},
+		Topics:    [][]common.Hash{e.eventTopics},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	elBlks, err := db.GetELBlocks(e.dbOperator, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	hists := make([]*db.ELContractParamHist, 0, len(logs))
+	for _, l := range logs {
+		if l.Removed || len(l.Topics) == 0 {
+			continue
+		}
+
+		height := int64(l.BlockNumber)
+
+		elBlk, ok := elBlks[height]
+		if !ok {
+			// Not stored by the el block indexer, which lags behind or starts at a later height, retry once it has the block.
+			return nil, fmt.Errorf("block %d not indexed by el block indexer", height)
+		} else if elBlk.Hash != l.BlockHash.Hex() {
+			// The chain reorganized after the el block indexer stored the block, wait for the rollback.
+			return nil, fmt.Errorf("%w: hash of block %d mismatch", ErrReorgDetected, height)
+		}
+
+		param, value, err := e.parseParamEvent(l)
+		if err != nil {
+			return nil, &BlockError{
+				Height: height,
+				Event:  fmt.Sprintf("log %d of tx %s", l.Index, l.TxHash.Hex()),
+				Err:    fmt.Errorf("parse log failed: %w", err),
+			}
+		}
+
+		hists = append(hists, &db.ELContractParamHist{
+			Param:       param,
+			Value:       value,
+			TxHash:      l.TxHash.Hex(),
+			LogIndex:    l.Index,
+			BlockHeight: height,
+			BlockHash:   elBlk.Hash,
+			BlockTime:   elBlk.Time,
+		})
+	}
+
+	return hists, nil
+}
+
+// parseParamEvent decodes a staking contract log by its topic into the parameter it changes and its new value.
+func (e *ELContractParamIndexer) parseParamEvent(l types.Log) (string, string, error) {
+	switch e.eventTopics2Name[l.Topics[0]] {
+	case EventFeeSet:
+		ev, err := e.elEventFilter.ParseFeeSet(l)
+		if err != nil {
+			return "", "", err
+		}
+
+		return ContractParamFee, ev.NewFee.String(), nil
+	case EventMinStakeAmountSet:
+		ev, err := e.elEventFilter.ParseMinStakeAmountSet(l)
+		if err != nil {
+			return "", "", err
+		}
+
+		return ContractParamMinStakeAmount, ev.MinStakeAmount.String(), nil
+	case EventMinUnstakeAmountSet:
+		ev, err := e.elEventFilter.ParseMinUnstakeAmountSet(l)
+		if err != nil {
+			return "", "", err
+		}
+
+		return ContractParamMinUnstakeAmount, ev.MinUnstakeAmount.String(), nil
+	case EventMinCommissionRateChanged:
+		ev, err := e.elEventFilter.ParseMinCommissionRateChanged(l)
+		if err != nil {
+			return "", "", err
+		}
+
+		return ContractParamMinCommissionRate, ev.MinCommissionRate.String(), nil
+	case EventOwnershipTransferStarted:
+		ev, err := e.elEventFilter.ParseOwnershipTransferStarted(l)
+		if err != nil {
+			return "", "", err
+		}
+
+		return ContractParamPendingOwner, strings.ToLower(ev.NewOwner.Hex()), nil
+	case EventOwnershipTransferred:
+		ev, err := e.elEventFilter.ParseOwnershipTransferred(l)
+		if err != nil {
+			return "", "", err
+		}
+
+		return ContractParamOwner, strings.ToLower(ev.NewOwner.Hex()), nil
+	default:
+		return "", "", fmt.Errorf("unexpected topic %s", l.Topics[0].Hex())
+	}
+}
+
+// getInitialParams reads the values of all parameters at the given block.
+func (e *ELContractParamIndexer) getInitialParams(height int64) ([]*db.ELContractParamHist, error) {
+	header, err := e.ethClient.HeaderByNumber(e.ctx, big.NewInt(height))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &bind.CallOpts{Context: e.ctx, BlockNumber: big.NewInt(height)}
+
+	fee, err := e.elCaller.Fee(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	minStakeAmount, err := e.elCaller.MinStakeAmount(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	minUnstakeAmount, err := e.elCaller.MinUnstakeAmount(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	minCommissionRate, err := e.elCaller.MinCommissionRate(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := e.elCaller.Owner(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{
+		ContractParamFee:               fee.String(),
+		ContractParamMinStakeAmount:    minStakeAmount.String(),
+		ContractParamMinUnstakeAmount:  minUnstakeAmount.String(),
+		ContractParamMinCommissionRate: minCommissionRate.String(),
+		ContractParamOwner:             strings.ToLower(owner.Hex()),
+	}
+
+	hists := make([]*db.ELContractParamHist, 0, len(values))
+	for param, value := range values {
+		hists = append(hists, &db.ELContractParamHist{
+			Param:       param,
+			Value:       value,
+			BlockHeight: height,
+			BlockHash:   header.Hash().Hex(),
+			BlockTime:   time.Unix(int64(header.Time), 0),
+		})
+	}
+
+	return hists, nil
+}
@@ -5,18 +5,20 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"time"
 
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	redis "github.com/redis/go-redis/v9"
-	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"github.com/piplabs/story-staking-api/cache"
 	"github.com/piplabs/story-staking-api/db"
 )
 
-var _ Indexer = (*ELRewardIndexer)(nil)
+var (
+	_ DependentIndexer = (*ELRewardIndexer)(nil)
+	_ Reindexer        = (*ELRewardIndexer)(nil)
+)
 
 type ELRewardIndexer struct {
 	ctx context.Context
@@ -25,9 +27,11 @@ type ELRewardIndexer struct {
 	cacheOperator *redis.Client
 
 	ethClient *ethclient.Client
+
+	fetchConcurrency int
 }
 
-func NewELRewardIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string) (*ELRewardIndexer, error) {
+func NewELRewardIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, fetchConcurrency int) (*ELRewardIndexer, error) {
 	ethClient, err := ethclient.Dial(rpcEndpoint)
 	if err != nil {
 		return nil, err
@@ -40,60 +44,89 @@ func NewELRewardIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator
 		cacheOperator: cacheOperator,
 
 		ethClient: ethClient,
+
+		fetchConcurrency: fetchConcurrency,
 	}, nil
 }
 
 func (e *ELRewardIndexer) Name() string {
-	return "el_reward"
+	return NameELReward
 }
 
-func (e *ELRewardIndexer) Run() {
-	log.Info().Str("indexer", e.Name()).Msg("Start indexing")
+func (e *ELRewardIndexer) ChainHead() (int64, error) {
+	return elChainHead(e.ctx, e.ethClient)
+}
 
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
+// Dependencies returns the el block indexer, only blocks that have been checked for reorg are indexed.
+func (e *ELRewardIndexer) Dependencies() []string {
+	return []string{NameELBlock}
+}
 
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-ticker.C:
-			indexPoint, err := db.GetIndexPoint(e.dbOperator, e.Name())
-			if err != nil {
-				log.Error().Err(err).Str("indexer", e.Name()).Msg("get index point failed")
-				continue
-			}
+func (e *ELRewardIndexer) Index(from, to int64) error {
+	elRewards, elRewardDeltas, err := e.getRewards(from, to)
+	if err != nil {
+		return err
+	}
 
-			latestBlkNum, err := e.ethClient.BlockNumber(e.ctx)
-			if err != nil {
-				log.Error().Err(err).Str("indexer", e.Name()).Msg("get latest el block failed")
-				continue
-			}
+	e.invalidateCache(elRewards)
 
-			if indexPoint.BlockHeight+10 > int64(latestBlkNum) {
-				continue
-			}
+	// Even if there are no entries, we also need to update the index point.
+	if err := db.BatchUpsertELRewards(e.dbOperator, e.Name(), elRewards, elRewardDeltas, to); err != nil {
+		return err
+	}
 
-			if err := e.index(indexPoint.BlockHeight+1, int64(latestBlkNum)); err != nil {
-				log.Error().Err(err).
-					Str("indexer", e.Name()).
-					Int64("from", indexPoint.BlockHeight+1).
-					Uint64("to", latestBlkNum).
-					Msg("index el reward failed")
-			}
-		}
+	return nil
+}
+
+func (e *ELRewardIndexer) Reindex(from, to int64) error {
+	elRewards, elRewardDeltas, err := e.getRewards(from, to)
+	if err != nil {
+		return err
+	}
+
+	rewardAddrs, err := db.ReplaceELRewards(e.dbOperator, from, to, elRewards, elRewardDeltas)
+	if err != nil {
+		return err
 	}
+
+	for _, addr := range rewardAddrs {
+		_ = cache.InvalidateRedisData(e.ctx, e.cacheOperator, cache.RewardsKey(addr))
+	}
+
+	return nil
 }
 
-func (e *ELRewardIndexer) index(from, to int64) error {
+// getRewards returns the rewards withdrawn in [from, to] summed by address, along with the per-block deltas.
+func (e *ELRewardIndexer) getRewards(from, to int64) ([]*db.ELReward, []*db.ELRewardDelta, error) {
+	blkHashes, err := db.GetELBlockHashes(e.dbOperator, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blks, err := fetchBlocks(e.ctx, from, to, e.fetchConcurrency, func(ctx context.Context, height int64) (*types.Block, error) {
+		return e.ethClient.BlockByNumber(ctx, big.NewInt(height))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
 	elRewardsMap := make(map[string]*db.ELReward)
+	elRewardDeltas := make([]*db.ELRewardDelta, 0)
+
+	for _, blk := range blks {
+		i := blk.Number().Int64()
 
-	for i := from; i <= to; i++ {
-		blk, err := e.ethClient.BlockByNumber(e.ctx, big.NewInt(i))
-		if err != nil {
-			return err
+		// Not stored by the el block indexer, which lags behind or starts at a later height, retry once it has the block.
+		if _, ok := blkHashes[i]; !ok {
+			return nil, nil, fmt.Errorf("block %d not indexed by el block indexer", i)
 		}
 
+		// The chain reorganized after the el block indexer stored the block, wait for the rollback.
+		if blkHashes[i] != blk.Hash().String() {
+			return nil, nil, fmt.Errorf("%w: hash of block %d mismatch", ErrReorgDetected, i)
+		}
+
+		blkRewardsMap := make(map[string]*big.Int)
 		for _, w := range blk.Withdrawals() {
 			address := strings.ToLower(w.Address.String())
 
@@ -105,14 +138,21 @@ func (e *ELRewardIndexer) index(from, to int64) error {
 
 			newRewards := big.NewInt(int64(w.Amount))
 
+			if _, ok := blkRewardsMap[address]; ok {
+				blkRewardsMap[address].Add(blkRewardsMap[address], newRewards)
+			} else {
+				blkRewardsMap[address] = newRewards
+			}
+
 			if _, ok := elRewardsMap[address]; ok {
 				curRewards := &big.Int{}
 				curRewards, success := curRewards.SetString(elRewardsMap[address].Amount, 10)
 				if !success {
-					return fmt.Errorf("parse current rewards failed: %s", elRewardsMap[address].Amount)
+					return nil, nil, fmt.Errorf("parse current rewards failed: %s", elRewardsMap[address].Amount)
 				}
 
 				elRewardsMap[address].Amount = curRewards.Add(curRewards, newRewards).String()
+				elRewardsMap[address].LastUpdateHeight = i
 			} else {
 				elRewardsMap[address] = &db.ELReward{
 					Address:          address,
@@ -122,35 +162,22 @@ func (e *ELRewardIndexer) index(from, to int64) error {
 			}
 		}
 
-		if len(elRewardsMap) > 100 {
-			elRewards := make([]*db.ELReward, 0, len(elRewardsMap))
-			for _, v := range elRewardsMap {
-				elRewards = append(elRewards, v)
-			}
-
-			e.invalidateCache(elRewards)
-
-			if err := db.BatchUpsertELRewards(e.dbOperator, e.Name(), elRewards, i); err != nil {
-				return err
-			}
-
-			elRewardsMap = make(map[string]*db.ELReward)
+		for address, amount := range blkRewardsMap {
+			elRewardDeltas = append(elRewardDeltas, &db.ELRewardDelta{
+				Address:     address,
+				BlockHeight: i,
+				Amount:      amount.String(),
+				BlockTime:   int64(blk.Time()),
+			})
 		}
 	}
 
-	// Handle remaining entries, even if there are no entries, we also need to update the index point.
 	elRewards := make([]*db.ELReward, 0, len(elRewardsMap))
 	for _, v := range elRewardsMap {
 		elRewards = append(elRewards, v)
 	}
 
-	e.invalidateCache(elRewards)
-
-	if err := db.BatchUpsertELRewards(e.dbOperator, e.Name(), elRewards, to); err != nil {
-		return err
-	}
-
-	return nil
+	return elRewards, elRewardDeltas, nil
 }
 
 func (e *ELRewardIndexer) invalidateCache(elRewards []*db.ELReward) {
@@ -1,15 +1,18 @@
 package indexer
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"math/big"
+	"slices"
 	"strings"
-	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	redis "github.com/redis/go-redis/v9"
-	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 
 	"github.com/piplabs/story-staking-api/db"
@@ -17,265 +20,274 @@ import (
 	"github.com/piplabs/story-staking-api/pkg/util"
 )
 
+var (
+	_ DependentIndexer = (*ELStakingEventIndexer)(nil)
+	_ Reindexer        = (*ELStakingEventIndexer)(nil)
+)
+
+// Names of the staking contract events that are indexed.
+const (
+	EventSetOperator               = "SetOperator"
+	EventUnsetOperator             = "UnsetOperator"
+	EventSetWithdrawalAddress      = "SetWithdrawalAddress"
+	EventSetRewardAddress          = "SetRewardAddress"
+	EventUpdateValidatorCommission = "UpdateValidatorCommission"
+	EventCreateValidator           = "CreateValidator"
+	EventDeposit                   = "Deposit"
+	EventRedelegate                = "Redelegate"
+	EventWithdraw                  = "Withdraw"
+	EventUnjail                    = "Unjail"
+)
+
+var stakingEventNames = []string{
+	EventSetOperator,
+	EventUnsetOperator,
+	EventSetWithdrawalAddress,
+	EventSetRewardAddress,
+	EventUpdateValidatorCommission,
+	EventCreateValidator,
+	EventDeposit,
+	EventRedelegate,
+	EventWithdraw,
+	EventUnjail,
+}
+
 type ELStakingEventIndexer struct {
 	ctx context.Context
 
 	dbOperator    *gorm.DB
 	cacheOperator *redis.Client
 
-	ethClient     *ethclient.Client
-	elEventFilter *iptokenstaking.IPTokenStakingFilterer
+	ethClient       *ethclient.Client
+	contractAddress common.Address
+	elEventFilter   *iptokenstaking.IPTokenStakingFilterer
+
+	eventTopics      []common.Hash
+	eventTopics2Name map[common.Hash]string
 }
 
-func NewELStakingEventIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string) (*ELStakingEventIndexer, error) {
+func NewELStakingEventIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, contractAddress common.Address) (*ELStakingEventIndexer, error) {
 	ethClient, err := ethclient.Dial(rpcEndpoint)
 	if err != nil {
 		return nil, err
 	}
 
-	elEventFilter, err := iptokenstaking.NewIPTokenStakingFilterer(iptokenstaking.ContractAddress, ethClient)
+	return newELStakingEventIndexer(ctx, dbOperator, cacheOperator, ethClient, contractAddress)
+}
+
+func newELStakingEventIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, ethClient *ethclient.Client, contractAddress common.Address) (*ELStakingEventIndexer, error) {
+	elEventFilter, err := iptokenstaking.NewIPTokenStakingFilterer(contractAddress, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	contractABI, err := iptokenstaking.IPTokenStakingMetaData.GetAbi()
 	if err != nil {
 		return nil, err
 	}
 
+	eventTopics := make([]common.Hash, 0, len(stakingEventNames))
+	eventTopics2Name := make(map[common.Hash]string, len(stakingEventNames))
+	for _, name := range stakingEventNames {
+		event, ok := contractABI.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("event %s not found in staking contract abi", name)
+		}
+
+		eventTopics = append(eventTopics, event.ID)
+		eventTopics2Name[event.ID] = name
+	}
+
 	return &ELStakingEventIndexer{
 		ctx: ctx,
 
 		dbOperator:    dbOperator,
 		cacheOperator: cacheOperator,
 
-		ethClient:     ethClient,
-		elEventFilter: elEventFilter,
+		ethClient:       ethClient,
+		contractAddress: contractAddress,
+		elEventFilter:   elEventFilter,
+
+		eventTopics:      eventTopics,
+		eventTopics2Name: eventTopics2Name,
 	}, nil
 }
 
 func (e *ELStakingEventIndexer) Name() string {
-	return "el_staking_event"
+	return NameELStakingEvent
 }
 
-func (e *ELStakingEventIndexer) Run() {
-	log.Info().Str("indexer", e.Name()).Msg("Start indexing")
-
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-e.ctx.Done():
-			return
-		case <-ticker.C:
-			indexPoint, err := db.GetIndexPoint(e.dbOperator, e.Name())
-			if err != nil {
-				log.Error().Err(err).Str("indexer", e.Name()).Msg("get index point failed")
-				continue
-			}
-
-			latestBlkNum, err := e.ethClient.BlockNumber(e.ctx)
-			if err != nil {
-				log.Error().Err(err).Str("indexer", e.Name()).Msg("get latest el block failed")
-				continue
-			}
-
-			if indexPoint.BlockHeight+10 > int64(latestBlkNum) {
-				continue
-			}
+func (e *ELStakingEventIndexer) ChainHead() (int64, error) {
+	return elChainHead(e.ctx, e.ethClient)
+}
 
-			if err := e.index(indexPoint.BlockHeight+1, int64(latestBlkNum)); err != nil {
-				log.Error().Err(err).
-					Str("indexer", e.Name()).
-					Int64("from", indexPoint.BlockHeight+1).
-					Uint64("to", latestBlkNum).
-					Msg("index el staking event failed")
-			}
-		}
-	}
+// Dependencies returns the el block indexer, only blocks that have been checked for reorg are indexed.
+func (e *ELStakingEventIndexer) Dependencies() []string {
+	return []string{NameELBlock}
 }
 
-func (e *ELStakingEventIndexer) index(from, to int64) error {
-	start := from
+func (e *ELStakingEventIndexer) Index(from, to int64) error {
+	stakingEvents, err := e.getStakingEvents(from, to)
+	if err != nil {
+		return err
+	}
 
-	for start <= to {
-		end := min(start+100, to)
+	if err := e.checkBlockHashes(from, to, stakingEvents); err != nil {
+		return err
+	}
 
-		stakingEvents, err := e.getStakingEvents(start, end)
-		if err != nil {
-			return err
-		}
+	return db.BatchCreateELStakingEvents(e.dbOperator, e.Name(), stakingEvents, to)
+}
 
-		if err := db.BatchCreateELStakingEvents(e.dbOperator, e.Name(), stakingEvents, end); err != nil {
-			return err
-		}
+func (e *ELStakingEventIndexer) Reindex(from, to int64) error {
+	stakingEvents, err := e.getStakingEvents(from, to)
+	if err != nil {
+		return err
+	}
 
-		start = end + 1
+	if err := e.checkBlockHashes(from, to, stakingEvents); err != nil {
+		return err
 	}
 
-	return nil
+	return db.ReplaceELStakingEvents(e.dbOperator, from, to, stakingEvents)
 }
 
 func (e *ELStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.ELStakingEvent, error) {
-	fromBlock, toBlock := uint64(from), uint64(to)
-
-	var elStakingEvents []*db.ELStakingEvent
-	// SetOperator event.
-	setOperatorEvents, err := e.elEventFilter.FilterSetOperator(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
+	logs, err := e.ethClient.FilterLogs(e.ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(from),
+		ToBlock:   big.NewInt(to),
+		Addresses: []common.Address{e.contractAddress},
+		Topics:    [][]common.Hash{e.eventTopics},
 	})
 	if err != nil {
 		return nil, err
 	}
-	defer setOperatorEvents.Close()
-
-	for setOperatorEvents.Next() {
-		ev := setOperatorEvents.Event
-
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:      ev.Raw.TxHash.Hex(),
-			BlockHeight: int64(ev.Raw.BlockNumber),
-			EventType:   TypeSetOperator,
-			Address:     strings.ToLower(ev.Delegator.Hex()),
-			DstAddress:  strings.ToLower(ev.Operator.Hex()),
-		})
-	}
 
-	// UnsetOperator event.
-	unsetOperatorEvents, err := e.elEventFilter.FilterUnsetOperator(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
+	return e.parseStakingEvents(logs)
+}
+
+// parseStakingEvents decodes the staking contract logs into events in chain order, a log that cannot be decoded
+// fails its block.
+func (e *ELStakingEventIndexer) parseStakingEvents(logs []types.Log) ([]*db.ELStakingEvent, error) {
+	// Nodes return the logs in chain order already, sort anyway as the order of operations relies on it.
+	slices.SortStableFunc(logs, func(a, b types.Log) int {
+		if a.BlockNumber != b.BlockNumber {
+			return cmp.Compare(a.BlockNumber, b.BlockNumber)
+		}
+
+		return cmp.Compare(a.Index, b.Index)
 	})
-	if err != nil {
-		return nil, err
-	}
-	defer unsetOperatorEvents.Close()
 
-	for unsetOperatorEvents.Next() {
-		ev := unsetOperatorEvents.Event
+	elStakingEvents := make([]*db.ELStakingEvent, 0, len(logs))
+	for _, l := range logs {
+		if l.Removed || len(l.Topics) == 0 {
+			continue
+		}
 
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:      ev.Raw.TxHash.Hex(),
-			BlockHeight: int64(ev.Raw.BlockNumber),
-			EventType:   TypeUnsetOperator,
-			Address:     strings.ToLower(ev.Delegator.Hex()),
-		})
-	}
+		ev, err := e.parseStakingEvent(l)
+		if err != nil {
+			return nil, &BlockError{
+				Height: int64(l.BlockNumber),
+				Event:  fmt.Sprintf("log %d of tx %s", l.Index, l.TxHash.Hex()),
+				Err:    fmt.Errorf("parse log failed: %w", err),
+			}
+		}
 
-	// SetWithdrawalAddress event.
-	setWithdrawalAddressEvents, err := e.elEventFilter.FilterSetWithdrawalAddress(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer setWithdrawalAddressEvents.Close()
-
-	for setWithdrawalAddressEvents.Next() {
-		ev := setWithdrawalAddressEvents.Event
-
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:      ev.Raw.TxHash.Hex(),
-			BlockHeight: int64(ev.Raw.BlockNumber),
-			EventType:   TypeSetWithdrawalAddress,
-			Address:     strings.ToLower(ev.Delegator.Hex()),
-			DstAddress:  strings.ToLower(common.BytesToAddress(ev.ExecutionAddress[:]).Hex()),
-		})
-	}
+		ev.TxHash = l.TxHash.Hex()
+		ev.BlockHash = l.BlockHash.Hex()
+		ev.BlockHeight = int64(l.BlockNumber)
+		ev.LogIndex = l.Index
 
-	// SetRewardAddress event.
-	setRewardAddressEvents, err := e.elEventFilter.FilterSetRewardAddress(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer setRewardAddressEvents.Close()
-
-	for setRewardAddressEvents.Next() {
-		ev := setRewardAddressEvents.Event
-
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:      ev.Raw.TxHash.Hex(),
-			BlockHeight: int64(ev.Raw.BlockNumber),
-			EventType:   TypeSetRewardAddress,
-			Address:     strings.ToLower(ev.Delegator.Hex()),
-			DstAddress:  strings.ToLower(common.BytesToAddress(ev.ExecutionAddress[:]).Hex()),
-		})
+		elStakingEvents = append(elStakingEvents, ev)
 	}
 
-	// UpdateValidatorCommission event.
-	updateValidatorCommissionEvents, err := e.elEventFilter.FilterUpdateValidatorCommission(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer updateValidatorCommissionEvents.Close()
+	return elStakingEvents, nil
+}
 
-	for updateValidatorCommissionEvents.Next() {
-		ev := updateValidatorCommissionEvents.Event
+// parseStakingEvent decodes a staking contract log by its topic, only the event specific fields are filled.
+func (e *ELStakingEventIndexer) parseStakingEvent(l types.Log) (*db.ELStakingEvent, error) {
+	switch e.eventTopics2Name[l.Topics[0]] {
+	case EventSetOperator:
+		ev, err := e.elEventFilter.ParseSetOperator(l)
+		if err != nil {
+			return nil, err
+		}
 
-		evmAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorCmpPubkey)
+		return &db.ELStakingEvent{
+			EventType:  TypeSetOperator,
+			Address:    strings.ToLower(ev.Delegator.Hex()),
+			DstAddress: strings.ToLower(ev.Operator.Hex()),
+		}, nil
+	case EventUnsetOperator:
+		ev, err := e.elEventFilter.ParseUnsetOperator(l)
 		if err != nil {
 			return nil, err
 		}
 
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:      ev.Raw.TxHash.Hex(),
-			BlockHeight: int64(ev.Raw.BlockNumber),
-			EventType:   TypeUpdateValidatorCommission,
-			Address:     strings.ToLower(evmAddr.Hex()),
-		})
-	}
+		return &db.ELStakingEvent{
+			EventType: TypeUnsetOperator,
+			Address:   strings.ToLower(ev.Delegator.Hex()),
+		}, nil
+	case EventSetWithdrawalAddress:
+		ev, err := e.elEventFilter.ParseSetWithdrawalAddress(l)
+		if err != nil {
+			return nil, err
+		}
 
-	// CreateValidator event.
-	createValidatorEvents, err := e.elEventFilter.FilterCreateValidator(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer createValidatorEvents.Close()
+		return &db.ELStakingEvent{
+			EventType:  TypeSetWithdrawalAddress,
+			Address:    strings.ToLower(ev.Delegator.Hex()),
+			DstAddress: strings.ToLower(common.BytesToAddress(ev.ExecutionAddress[:]).Hex()),
+		}, nil
+	case EventSetRewardAddress:
+		ev, err := e.elEventFilter.ParseSetRewardAddress(l)
+		if err != nil {
+			return nil, err
+		}
+
+		return &db.ELStakingEvent{
+			EventType:  TypeSetRewardAddress,
+			Address:    strings.ToLower(ev.Delegator.Hex()),
+			DstAddress: strings.ToLower(common.BytesToAddress(ev.ExecutionAddress[:]).Hex()),
+		}, nil
+	case EventUpdateValidatorCommission:
+		ev, err := e.elEventFilter.ParseUpdateValidatorCommission(l)
+		if err != nil {
+			return nil, err
+		}
 
-	for createValidatorEvents.Next() {
-		ev := createValidatorEvents.Event
+		evmAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorCmpPubkey)
+		if err != nil {
+			return nil, err
+		}
+
+		return &db.ELStakingEvent{
+			EventType: TypeUpdateValidatorCommission,
+			Address:   strings.ToLower(evmAddr.Hex()),
+		}, nil
+	case EventCreateValidator:
+		ev, err := e.elEventFilter.ParseCreateValidator(l)
+		if err != nil {
+			return nil, err
+		}
 
 		valAddr := strings.ToLower(ev.OperatorAddress.Hex())
 
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:              ev.Raw.TxHash.Hex(),
-			BlockHeight:         int64(ev.Raw.BlockNumber),
+		return &db.ELStakingEvent{
 			EventType:           TypeCreateValidator,
 			Address:             valAddr,
 			DstValidatorAddress: valAddr,
-		})
-	}
-
-	// Deposit event.
-	depositEvents, err := e.elEventFilter.FilterDeposit(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer depositEvents.Close()
-
-	for depositEvents.Next() {
-		ev := depositEvents.Event
+		}, nil
+	case EventDeposit:
+		ev, err := e.elEventFilter.ParseDeposit(l)
+		if err != nil {
+			return nil, err
+		}
 
-		eventType := TypeStake
+		// The delegator of the operations sent on its behalf is kept as the destination address.
+		eventType, delAddr := TypeStake, ""
 		if ev.OperatorAddress.Hex() != ev.Delegator.Hex() {
-			eventType = TypeStakeOnBehalf
+			eventType, delAddr = TypeStakeOnBehalf, strings.ToLower(ev.Delegator.Hex())
 		}
 
 		valAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorCmpPubkey)
@@ -283,32 +295,24 @@ func (e *ELStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.ELStakin
 			return nil, err
 		}
 
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:              ev.Raw.TxHash.Hex(),
-			BlockHeight:         int64(ev.Raw.BlockNumber),
+		return &db.ELStakingEvent{
 			EventType:           eventType,
 			Address:             strings.ToLower(ev.OperatorAddress.Hex()),
 			DstValidatorAddress: strings.ToLower(valAddr.Hex()),
-		})
-	}
-
-	// Redelegate event.
-	redelegateEvents, err := e.elEventFilter.FilterRedelegate(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer redelegateEvents.Close()
-
-	for redelegateEvents.Next() {
-		ev := redelegateEvents.Event
+			DstAddress:          delAddr,
+			StakeAmount:         ev.StakeAmount.String(),
+			StakingPeriod:       ev.StakingPeriod.String(),
+			DelegationID:        ev.DelegationId.String(),
+		}, nil
+	case EventRedelegate:
+		ev, err := e.elEventFilter.ParseRedelegate(l)
+		if err != nil {
+			return nil, err
+		}
 
-		eventType := TypeRedelegate
+		eventType, delAddr := TypeRedelegate, ""
 		if ev.OperatorAddress.Hex() != ev.Delegator.Hex() {
-			eventType = TypeRedelegateOnBehalf
+			eventType, delAddr = TypeRedelegateOnBehalf, strings.ToLower(ev.Delegator.Hex())
 		}
 
 		srcValAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorSrcCmpPubkey)
@@ -321,33 +325,24 @@ func (e *ELStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.ELStakin
 			return nil, err
 		}
 
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:              ev.Raw.TxHash.Hex(),
-			BlockHeight:         int64(ev.Raw.BlockNumber),
+		return &db.ELStakingEvent{
 			EventType:           eventType,
 			Address:             strings.ToLower(ev.OperatorAddress.Hex()),
 			SrcValidatorAddress: strings.ToLower(srcValAddr.Hex()),
 			DstValidatorAddress: strings.ToLower(dstValAddr.Hex()),
-		})
-	}
-
-	// Withdraw event.
-	withdrawEvents, err := e.elEventFilter.FilterWithdraw(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer withdrawEvents.Close()
-
-	for withdrawEvents.Next() {
-		ev := withdrawEvents.Event
+			DstAddress:          delAddr,
+			StakeAmount:         ev.Amount.String(),
+			DelegationID:        ev.DelegationId.String(),
+		}, nil
+	case EventWithdraw:
+		ev, err := e.elEventFilter.ParseWithdraw(l)
+		if err != nil {
+			return nil, err
+		}
 
-		eventType := TypeUnstake
+		eventType, delAddr := TypeUnstake, ""
 		if ev.OperatorAddress.Hex() != ev.Delegator.Hex() {
-			eventType = TypeUnstakeOnBehalf
+			eventType, delAddr = TypeUnstakeOnBehalf, strings.ToLower(ev.Delegator.Hex())
 		}
 
 		valAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorCmpPubkey)
@@ -355,28 +350,19 @@ func (e *ELStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.ELStakin
 			return nil, err
 		}
 
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:              ev.Raw.TxHash.Hex(),
-			BlockHeight:         int64(ev.Raw.BlockNumber),
+		return &db.ELStakingEvent{
 			EventType:           eventType,
 			Address:             strings.ToLower(ev.OperatorAddress.Hex()),
 			DstValidatorAddress: strings.ToLower(valAddr.Hex()),
-		})
-	}
-
-	// Unjail event.
-	unjailEvents, err := e.elEventFilter.FilterUnjail(&bind.FilterOpts{
-		Context: e.ctx,
-		Start:   fromBlock,
-		End:     &toBlock,
-	})
-	if err != nil {
-		return nil, err
-	}
-	defer unjailEvents.Close()
-
-	for unjailEvents.Next() {
-		ev := unjailEvents.Event
+			DstAddress:          delAddr,
+			StakeAmount:         ev.StakeAmount.String(),
+			DelegationID:        ev.DelegationId.String(),
+		}, nil
+	case EventUnjail:
+		ev, err := e.elEventFilter.ParseUnjail(l)
+		if err != nil {
+			return nil, err
+		}
 
 		valAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorCmpPubkey)
 		if err != nil {
@@ -388,14 +374,34 @@ func (e *ELStakingEventIndexer) getStakingEvents(from, to int64) ([]*db.ELStakin
 			eventType = TypeUnjailOnBehalf
 		}
 
-		elStakingEvents = append(elStakingEvents, &db.ELStakingEvent{
-			TxHash:              ev.Raw.TxHash.Hex(),
-			BlockHeight:         int64(ev.Raw.BlockNumber),
+		return &db.ELStakingEvent{
 			EventType:           eventType,
 			Address:             strings.ToLower(ev.Unjailer.Hex()),
 			DstValidatorAddress: strings.ToLower(valAddr.Hex()),
-		})
+		}, nil
+	default:
+		return nil, fmt.Errorf("unexpected topic %s", l.Topics[0].Hex())
 	}
+}
 
-	return elStakingEvents, nil
+// checkBlockHashes ensures the events were emitted by the blocks stored by the el block indexer,
+// otherwise the chain reorganized and the el block indexer will roll back to the fork point.
+func (e *ELStakingEventIndexer) checkBlockHashes(from, to int64, events []*db.ELStakingEvent) error {
+	blkHashes, err := db.GetELBlockHashes(e.dbOperator, from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, ev := range events {
+		// Not stored by the el block indexer, which lags behind or starts at a later height, retry once it has the block.
+		if _, ok := blkHashes[ev.BlockHeight]; !ok {
+			return fmt.Errorf("block %d not indexed by el block indexer", ev.BlockHeight)
+		}
+
+		if blkHashes[ev.BlockHeight] != ev.BlockHash {
+			return fmt.Errorf("%w: hash of block %d mismatch", ErrReorgDetected, ev.BlockHeight)
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,96 @@
+package indexer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/piplabs/story-staking-api/pkg/indexer/contract/iptokenstaking"
+)
+
+func TestParseStakingEvents(t *testing.T) {
+	e, err := newELStakingEventIndexer(context.Background(), nil, nil, nil, iptokenstaking.ContractAddress)
+	require.NoError(t, err)
+
+	contractABI, err := iptokenstaking.IPTokenStakingMetaData.GetAbi()
+	require.NoError(t, err)
+
+	stakingLog := func(name string, blockNumber uint64, index uint, args ...interface{}) types.Log {
+		event := contractABI.Events[name]
+		data, err := event.Inputs.Pack(args...)
+		require.NoError(t, err)
+
+		return types.Log{
+			Topics:      []common.Hash{event.ID},
+			Data:        data,
+			BlockNumber: blockNumber,
+			TxHash:      common.BigToHash(big.NewInt(int64(blockNumber))),
+			Index:       index,
+		}
+	}
+
+	val1, err := base64.StdEncoding.DecodeString("Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY")
+	require.NoError(t, err)
+	val2, err := base64.StdEncoding.DecodeString("AsYEf5RB7X1tMEVAbpXAfNhcd45LjO88p6usCblccJ7l")
+	require.NoError(t, err)
+
+	delegator := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	operator := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+
+	removed := stakingLog(EventSetOperator, 1, 0, delegator, operator)
+	removed.Removed = true
+
+	// The logs are out of chain order.
+	events, err := e.parseStakingEvents([]types.Log{
+		stakingLog(EventWithdraw, 2, 1, delegator, val1, big.NewInt(2e18), big.NewInt(7), operator, []byte{}),
+		stakingLog(EventDeposit, 1, 5, delegator, val1, big.NewInt(1e18), big.NewInt(0), big.NewInt(7), delegator, []byte{}),
+		removed,
+		stakingLog(EventSetOperator, 1, 2, delegator, operator),
+		stakingLog(EventRedelegate, 2, 0, delegator, val1, val2, big.NewInt(7), delegator, big.NewInt(3e18)),
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, len(events))
+
+	require.Equal(t, TypeSetOperator, events[0].EventType)
+	require.Equal(t, int64(1), events[0].BlockHeight)
+	require.Equal(t, uint(2), events[0].LogIndex)
+	require.Equal(t, "0x00000000000000000000000000000000000000d1", events[0].Address)
+	require.Equal(t, "0x00000000000000000000000000000000000000e1", events[0].DstAddress)
+
+	require.Equal(t, TypeStake, events[1].EventType)
+	require.Equal(t, uint(5), events[1].LogIndex)
+	require.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", events[1].DstValidatorAddress)
+	require.Equal(t, "1000000000000000000", events[1].StakeAmount)
+	require.Equal(t, "7", events[1].DelegationID)
+	require.Equal(t, "", events[1].DstAddress)
+
+	require.Equal(t, TypeRedelegate, events[2].EventType)
+	require.Equal(t, int64(2), events[2].BlockHeight)
+	require.Equal(t, "0x7e5f4552091a69125d5dfcb7b8c2659029395bdf", events[2].SrcValidatorAddress)
+	require.Equal(t, "0x2b5ad5c4795c026514f8317c7a215e218dccd6cf", events[2].DstValidatorAddress)
+	require.Equal(t, "3000000000000000000", events[2].StakeAmount)
+
+	// Sent by the operator on behalf of the delegator, kept as the destination address.
+	require.Equal(t, TypeUnstakeOnBehalf, events[3].EventType)
+	require.Equal(t, uint(1), events[3].LogIndex)
+	require.Equal(t, "0x00000000000000000000000000000000000000e1", events[3].Address)
+	require.Equal(t, "0x00000000000000000000000000000000000000d1", events[3].DstAddress)
+
+	t.Run("unknown topic", func(t *testing.T) {
+		_, err := e.parseStakingEvents([]types.Log{
+			stakingLog(EventSetOperator, 1, 0, delegator, operator),
+			{Topics: []common.Hash{common.HexToHash("0x01")}, BlockNumber: 3, Index: 4},
+		})
+
+		var blockErr *BlockError
+		require.True(t, errors.As(err, &blockErr))
+		require.Equal(t, int64(3), blockErr.Height)
+		require.Contains(t, blockErr.Event, "log 4")
+	})
+}
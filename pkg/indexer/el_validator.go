@@ -0,0 +1,302 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	redis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer/contract/iptokenstaking"
+	"github.com/piplabs/story-staking-api/pkg/util"
+)
+
+var (
+	_ DependentIndexer = (*ELValidatorIndexer)(nil)
+	_ Reindexer        = (*ELValidatorIndexer)(nil)
+)
+
+type ELValidatorIndexer struct {
+	ctx context.Context
+
+	dbOperator    *gorm.DB
+	cacheOperator *redis.Client
+
+	ethClient       *ethclient.Client
+	contractAddress common.Address
+	elEventFilter   *iptokenstaking.IPTokenStakingFilterer
+
+	eventTopics      []common.Hash
+	eventTopics2Name map[common.Hash]string
+}
+
+func NewELValidatorIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, contractAddress common.Address) (*ELValidatorIndexer, error) {
+	ethClient, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	elEventFilter, err := iptokenstaking.NewIPTokenStakingFilterer(contractAddress, ethClient)
+	if err != nil {
+		return nil, err
+	}
+
+	contractABI, err := iptokenstaking.IPTokenStakingMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	eventNames := []string{EventCreateValidator, EventUpdateValidatorCommission}
+	eventTopics := make([]common.Hash, 0, len(eventNames))
+	eventTopics2Name := make(map[common.Hash]string, len(eventNames))
+	for _, name := range eventNames {
+		event, ok := contractABI.Events[name]
+		if !ok {
+			return nil, fmt.Errorf("event %s not found in staking contract abi", name)
+		}
+
+		eventTopics = append(eventTopics, event.ID)
+		eventTopics2Name[event.ID] = name
+	}
+
+	return &ELValidatorIndexer{
+		ctx: ctx,
+
+		dbOperator:    dbOperator,
+		cacheOperator: cacheOperator,
+
+		ethClient:       ethClient,
+		contractAddress: contractAddress,
+		elEventFilter:   elEventFilter,
+
+		eventTopics:      eventTopics,
+		eventTopics2Name: eventTopics2Name,
+	}, nil
+}
+
+func (e *ELValidatorIndexer) Name() string {
+	return NameELValidator
+}
+
+func (e *ELValidatorIndexer) ChainHead() (int64, error) {
+	return elChainHead(e.ctx, e.ethClient)
+}
+
+// Dependencies returns the el block indexer, only blocks that have been checked for reorg are indexed, and the
+// el staking event indexer the outcome of the events on the consensus layer is matched through. The cl staking
+// event indexer follows the heights of the other layer, the outcome is waited for by getValidators instead.
+func (e *ELValidatorIndexer) Dependencies() []string {
+	return []string{NameELBlock, NameELStakingEvent}
+}
+
+// Index indexes the blocks of [from, to] up to the first one with an event the consensus layer has not executed yet,
+// the index point is left before it and the rest is indexed once the cl staking event indexer has the outcome.
+func (e *ELValidatorIndexer) Index(from, to int64) error {
+	validators, hists, executedTo, err := e.getValidators(from, to)
+	if err != nil {
+		return err
+	} else if executedTo < from {
+		return nil
+	}
+
+	return db.BatchCreateValidators(e.dbOperator, e.Name(), validators, hists, executedTo)
+}
+
+func (e *ELValidatorIndexer) Reindex(from, to int64) error {
+	validators, hists, executedTo, err := e.getValidators(from, to)
+	if err != nil {
+		return err
+	} else if executedTo < to {
+		return fmt.Errorf("block %d has events not executed by the consensus layer yet", executedTo+1)
+	}
+
+	return db.ReplaceValidators(e.dbOperator, from, to, validators, hists)
+}
+
+// getValidators returns the validators registered in [from, to] and the commission rates set in the range,
+// including the initial rates of the registered validators. The events rejected by the consensus layer are left out.
+// The range stops before the first block with an event the consensus layer has not executed yet, its last block is
+// returned.
+func (e *ELValidatorIndexer) getValidators(from, to int64) ([]*db.Validator, []*db.ValidatorCommissionHist, int64, error) {
+	logs, err := e.ethClient.FilterLogs(e.ctx, ethereum.FilterQuery{
+		FromBlock: big.NewInt(from),
+		ToBlock:   big.NewInt(to),
+		Addresses: []common.Address{e.contractAddress},
+		Topics:    [][]common.Hash{e.eventTopics},
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	elBlks, err := db.GetELBlocks(e.dbOperator, from, to)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	statuses, err := e.getEventStatuses(logs)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	for _, l := range logs {
+		if l.Removed || len(l.Topics) == 0 {
+			continue
+		}
+
+		// The consensus layer executes the event in a later block.
+		if _, ok := statuses[eventKey{txHash: l.TxHash.Hex(), logIndex: l.Index}]; !ok {
+			to = min(to, int64(l.BlockNumber)-1)
+		}
+	}
+
+	var (
+		validators []*db.Validator
+		hists      []*db.ValidatorCommissionHist
+	)
+	for _, l := range logs {
+		if l.Removed || len(l.Topics) == 0 {
+			continue
+		}
+
+		height := int64(l.BlockNumber)
+		if height > to {
+			continue
+		}
+
+		elBlk, ok := elBlks[height]
+		if !ok {
+			// Not stored by the el block indexer, which lags behind or starts at a later height, retry once it has the block.
+			return nil, nil, 0, fmt.Errorf("block %d not indexed by el block indexer", height)
+		} else if elBlk.Hash != l.BlockHash.Hex() {
+			// The chain reorganized after the el block indexer stored the block, wait for the rollback.
+			return nil, nil, 0, fmt.Errorf("%w: hash of block %d mismatch", ErrReorgDetected, height)
+		}
+
+		validator, hist, err := e.parseValidatorEvent(l)
+		if err != nil {
+			return nil, nil, 0, &BlockError{
+				Height: height,
+				Event:  fmt.Sprintf("log %d of tx %s", l.Index, l.TxHash.Hex()),
+				Err:    fmt.Errorf("parse log failed: %w", err),
+			}
+		}
+
+		if !statuses[eventKey{txHash: l.TxHash.Hex(), logIndex: l.Index}] {
+			continue
+		}
+
+		if validator != nil {
+			validator.TxHash = l.TxHash.Hex()
+			validator.BlockHeight = height
+			validator.BlockHash = elBlk.Hash
+			validator.BlockTime = elBlk.Time
+
+			validators = append(validators, validator)
+		}
+
+		hist.TxHash = l.TxHash.Hex()
+		hist.LogIndex = l.Index
+		hist.BlockHeight = height
+		hist.BlockHash = elBlk.Hash
+		hist.BlockTime = elBlk.Time
+
+		hists = append(hists, hist)
+	}
+
+	return validators, hists, to, nil
+}
+
+type eventKey struct {
+	txHash   string
+	logIndex uint
+}
+
+// getEventStatuses returns the outcome on the consensus layer of the logs it already executed.
+func (e *ELValidatorIndexer) getEventStatuses(logs []types.Log) (map[eventKey]bool, error) {
+	txHashes := make([]string, 0, len(logs))
+	for _, l := range logs {
+		txHashes = append(txHashes, l.TxHash.Hex())
+	}
+	if len(txHashes) == 0 {
+		return nil, nil
+	}
+
+	statuses, err := db.GetELStakingEventStatuses(e.dbOperator, txHashes)
+	if err != nil {
+		return nil, fmt.Errorf("get el staking event statuses failed: %w", err)
+	}
+
+	res := make(map[eventKey]bool, len(statuses))
+	for _, status := range statuses {
+		res[eventKey{txHash: status.TxHash, logIndex: status.LogIndex}] = status.StatusOK
+	}
+
+	return res, nil
+}
+
+// parseValidatorEvent decodes a staking contract log by its topic, the validator is only returned for
+// CreateValidator events. Only the event specific fields are filled.
+func (e *ELValidatorIndexer) parseValidatorEvent(l types.Log) (*db.Validator, *db.ValidatorCommissionHist, error) {
+	switch e.eventTopics2Name[l.Topics[0]] {
+	case EventCreateValidator:
+		ev, err := e.elEventFilter.ParseCreateValidator(l)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		evmAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorCmpPubkey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cometAddr, err := util.CmpPubKeyToCometAddress(ev.ValidatorCmpPubkey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		valAddr := strings.ToLower(evmAddr.Hex())
+
+		validator := &db.Validator{
+			CmpPubkey:               hexutil.Encode(ev.ValidatorCmpPubkey),
+			EVMAddress:              valAddr,
+			ConsensusAddress:        cometAddr,
+			OperatorAddress:         strings.ToLower(ev.OperatorAddress.Hex()),
+			Moniker:                 ev.Moniker,
+			StakeAmount:             ev.StakeAmount.String(),
+			SupportTokenType:        int(ev.SupportsUnlocked),
+			CommissionRate:          int64(ev.CommissionRate),
+			MaxCommissionRate:       int64(ev.MaxCommissionRate),
+			MaxCommissionChangeRate: int64(ev.MaxCommissionChangeRate),
+		}
+
+		return validator, &db.ValidatorCommissionHist{
+			EVMAddress:     valAddr,
+			CommissionRate: int64(ev.CommissionRate),
+		}, nil
+	case EventUpdateValidatorCommission:
+		ev, err := e.elEventFilter.ParseUpdateValidatorCommission(l)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		evmAddr, err := util.CmpPubKeyToEVMAddress(ev.ValidatorCmpPubkey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, &db.ValidatorCommissionHist{
+			EVMAddress:     strings.ToLower(evmAddr.Hex()),
+			CommissionRate: int64(ev.CommissionRate),
+		}, nil
+	default:
+		return nil, nil, fmt.Errorf("unexpected topic %s", l.Topics[0].Hex())
+	}
+}
@@ -0,0 +1,187 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	redis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/cache"
+	"github.com/piplabs/story-staking-api/db"
+)
+
+var (
+	_ DependentIndexer = (*ELWithdrawalIndexer)(nil)
+	_ Reindexer        = (*ELWithdrawalIndexer)(nil)
+)
+
+// ELWithdrawalIndexer indexes the unstake and UBI withdrawals, reward withdrawals are indexed by the el reward indexer.
+type ELWithdrawalIndexer struct {
+	ctx context.Context
+
+	dbOperator    *gorm.DB
+	cacheOperator *redis.Client
+
+	ethClient *ethclient.Client
+
+	fetchConcurrency int
+}
+
+func NewELWithdrawalIndexer(ctx context.Context, dbOperator *gorm.DB, cacheOperator *redis.Client, rpcEndpoint string, fetchConcurrency int) (*ELWithdrawalIndexer, error) {
+	ethClient, err := ethclient.Dial(rpcEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ELWithdrawalIndexer{
+		ctx: ctx,
+
+		dbOperator:    dbOperator,
+		cacheOperator: cacheOperator,
+
+		ethClient: ethClient,
+
+		fetchConcurrency: fetchConcurrency,
+	}, nil
+}
+
+func (e *ELWithdrawalIndexer) Name() string {
+	return NameELWithdrawal
+}
+
+func (e *ELWithdrawalIndexer) ChainHead() (int64, error) {
+	return elChainHead(e.ctx, e.ethClient)
+}
+
+// Dependencies returns the el block indexer, only blocks that have been checked for reorg are indexed.
+func (e *ELWithdrawalIndexer) Dependencies() []string {
+	return []string{NameELBlock}
+}
+
+func (e *ELWithdrawalIndexer) Index(from, to int64) error {
+	withdrawals, deltas, err := e.getWithdrawals(from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range withdrawals {
+		_ = cache.InvalidateRedisData(e.ctx, e.cacheOperator, cache.WithdrawalsKey(w.Kind, w.Address))
+		// Only the unbondings paid out to the delegator itself are known by address, the others expire shortly.
+		if w.Kind == WithdrawalKindUnstake {
+			_ = cache.InvalidateRedisData(e.ctx, e.cacheOperator, cache.UnbondingsKey(w.Address))
+		}
+	}
+
+	return db.BatchUpsertELWithdrawals(e.dbOperator, e.Name(), withdrawals, deltas, to)
+}
+
+func (e *ELWithdrawalIndexer) Reindex(from, to int64) error {
+	withdrawals, deltas, err := e.getWithdrawals(from, to)
+	if err != nil {
+		return err
+	}
+
+	withdrawalAddrs, err := db.ReplaceELWithdrawals(e.dbOperator, from, to, withdrawals, deltas)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range withdrawalAddrs {
+		for _, kind := range WithdrawalKinds {
+			_ = cache.InvalidateRedisData(e.ctx, e.cacheOperator, cache.WithdrawalsKey(kind, addr))
+		}
+		_ = cache.InvalidateRedisData(e.ctx, e.cacheOperator, cache.UnbondingsKey(addr))
+	}
+
+	return nil
+}
+
+// getWithdrawals returns the withdrawals in [from, to] summed by address and kind, along with the per-block deltas.
+func (e *ELWithdrawalIndexer) getWithdrawals(from, to int64) ([]*db.ELWithdrawal, []*db.ELWithdrawalDelta, error) {
+	blkHashes, err := db.GetELBlockHashes(e.dbOperator, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blks, err := fetchBlocks(e.ctx, from, to, e.fetchConcurrency, func(ctx context.Context, height int64) (*types.Block, error) {
+		return e.ethClient.BlockByNumber(ctx, big.NewInt(height))
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type withdrawalKey struct {
+		address string
+		kind    string
+	}
+
+	withdrawalsMap := make(map[withdrawalKey]*big.Int)
+	lastUpdateHeights := make(map[withdrawalKey]int64)
+	deltas := make([]*db.ELWithdrawalDelta, 0)
+
+	for _, blk := range blks {
+		i := blk.Number().Int64()
+
+		// Not stored by the el block indexer, which lags behind or starts at a later height, retry once it has the block.
+		if _, ok := blkHashes[i]; !ok {
+			return nil, nil, fmt.Errorf("block %d not indexed by el block indexer", i)
+		}
+
+		// The chain reorganized after the el block indexer stored the block, wait for the rollback.
+		if blkHashes[i] != blk.Hash().String() {
+			return nil, nil, fmt.Errorf("%w: hash of block %d mismatch", ErrReorgDetected, i)
+		}
+
+		blkWithdrawalsMap := make(map[withdrawalKey]*big.Int)
+		for _, w := range blk.Withdrawals() {
+			// In story, we use `Validator` field to store the withdrawal type.
+			kind, ok := withdrawalType2Kind[w.Validator]
+			if !ok {
+				continue
+			}
+
+			key := withdrawalKey{address: strings.ToLower(w.Address.String()), kind: kind}
+			amount := new(big.Int).SetUint64(w.Amount)
+
+			if _, ok := blkWithdrawalsMap[key]; ok {
+				blkWithdrawalsMap[key].Add(blkWithdrawalsMap[key], amount)
+			} else {
+				blkWithdrawalsMap[key] = amount
+			}
+		}
+
+		for key, amount := range blkWithdrawalsMap {
+			deltas = append(deltas, &db.ELWithdrawalDelta{
+				Address:     key.address,
+				Kind:        key.kind,
+				BlockHeight: i,
+				Amount:      amount.String(),
+				BlockTime:   int64(blk.Time()),
+			})
+
+			if _, ok := withdrawalsMap[key]; ok {
+				withdrawalsMap[key].Add(withdrawalsMap[key], amount)
+			} else {
+				withdrawalsMap[key] = new(big.Int).Set(amount)
+			}
+			lastUpdateHeights[key] = i
+		}
+	}
+
+	withdrawals := make([]*db.ELWithdrawal, 0, len(withdrawalsMap))
+	for key, amount := range withdrawalsMap {
+		withdrawals = append(withdrawals, &db.ELWithdrawal{
+			Address:          key.address,
+			Kind:             key.kind,
+			Amount:           amount.String(),
+			LastUpdateHeight: lastUpdateHeights[key],
+		})
+	}
+
+	return withdrawals, deltas, nil
+}
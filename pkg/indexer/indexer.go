@@ -1,6 +1,69 @@
 package indexer
 
+import "strings"
+
+const (
+	NameCLBlock              = "cl_block"
+	NameCLStakingEvent       = "cl_staking_event"
+	NameCLValidatorVote      = "cl_validator_vote"
+	NameCLTotalStakeHist     = "cl_total_stake_hist"
+	NameCLValidatorStakeHist = "cl_validator_stake_hist"
+	NameELBlock              = "el_block"
+	NameELReward             = "el_reward"
+	NameELStakingEvent       = "el_staking_event"
+	NameELContractParam      = "el_contract_param"
+	NameELValidator          = "el_validator"
+	NameELWithdrawal         = "el_withdrawal"
+)
+
+const (
+	LayerCL = "cl"
+	LayerEL = "el"
+)
+
+// Names lists all indexers known to the service.
+var Names = []string{
+	NameCLBlock,
+	NameCLStakingEvent,
+	NameCLValidatorVote,
+	NameCLTotalStakeHist,
+	NameCLValidatorStakeHist,
+	NameELBlock,
+	NameELReward,
+	NameELStakingEvent,
+	NameELContractParam,
+	NameELValidator,
+	NameELWithdrawal,
+}
+
+// Layer returns the chain layer followed by the indexer, LayerCL or LayerEL.
+func Layer(name string) string {
+	if strings.HasPrefix(name, LayerEL+"_") {
+		return LayerEL
+	}
+
+	return LayerCL
+}
+
 type Indexer interface {
-	Run()
 	Name() string
+	// ChainHead returns the latest block height of the chain the indexer follows.
+	ChainHead() (int64, error)
+	// Index indexes the blocks in [from, to] and advances the index point to `to`.
+	Index(from, to int64) error
+}
+
+// DependentIndexer is implemented by indexers that consume the data of other indexers,
+// they are never run ahead of the index points of their dependencies.
+type DependentIndexer interface {
+	Indexer
+	Dependencies() []string
+}
+
+// Reindexer is implemented by indexers whose data can be rebuilt for a range of blocks that was already indexed.
+type Reindexer interface {
+	Indexer
+	// Reindex deletes and rebuilds the data of the blocks in [from, to] in a single transaction,
+	// the index point is left untouched.
+	Reindex(from, to int64) error
 }
@@ -0,0 +1,234 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/metrics"
+)
+
+// Runner drives an indexer: it polls the chain head, or follows it through a subscription when enabled,
+// keeps the configured confirmation depth and feeds the missing blocks to the indexer in batches.
+type Runner struct {
+	dbOperator *gorm.DB
+
+	indexer Indexer
+	conf    Config
+
+	// Consecutive failures of the same block, see Config.MaxRetries.
+	failedHeight int64
+	failures     int
+}
+
+func NewRunner(dbOperator *gorm.DB, indexer Indexer, conf Config) *Runner {
+	return &Runner{
+		dbOperator: dbOperator,
+
+		indexer: indexer,
+		conf:    conf.WithDefaults(),
+	}
+}
+
+func (r *Runner) Name() string {
+	return r.indexer.Name()
+}
+
+// Run indexes until the context is done.
+func (r *Runner) Run(ctx context.Context) {
+	log.Info().
+		Str("indexer", r.Name()).
+		Dur("interval", r.conf.Interval).
+		Int64("confirmations", *r.conf.Confirmations).
+		Int64("batch_size", r.conf.BatchSize).
+		Bool("subscribe", r.conf.Subscribe).
+		Msg("Start indexing")
+
+	ticker := time.NewTicker(r.conf.Interval)
+	defer ticker.Stop()
+
+	var heads <-chan int64
+	for {
+		if heads == nil {
+			heads = r.subscribe(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			chainHead, err := r.indexer.ChainHead()
+			if err != nil {
+				r.handleError(fmt.Errorf("get chain head failed: %w", err))
+				continue
+			}
+
+			r.handleError(r.run(ctx, chainHead))
+		case chainHead, ok := <-heads:
+			if !ok {
+				log.Warn().Str("indexer", r.Name()).Msg("subscription dropped, fall back to polling")
+				heads = nil
+				continue
+			}
+
+			r.handleError(r.run(ctx, chainHead))
+		}
+	}
+}
+
+// subscribe returns the stream of new chain heads if subscription is enabled and supported,
+// otherwise nil, which blocks forever and leaves the runner polling.
+func (r *Runner) subscribe(ctx context.Context) <-chan int64 {
+	if !r.conf.Subscribe {
+		return nil
+	}
+
+	subscriber, ok := r.indexer.(HeadSubscriber)
+	if !ok {
+		return nil
+	}
+
+	heads, err := subscriber.SubscribeHead(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("indexer", r.Name()).Msg("subscribe chain head failed, fall back to polling")
+		return nil
+	}
+
+	log.Info().Str("indexer", r.Name()).Msg("subscribed to chain head")
+
+	return heads
+}
+
+// handleError logs the error of an indexing round and records it on the index point. Only the blocks of
+// reindexers are quarantined, the others could never be replayed.
+func (r *Runner) handleError(err error) {
+	var blockErr *BlockError
+	if _, ok := r.indexer.(Reindexer); !ok || !errors.As(err, &blockErr) {
+		r.failures = 0
+	} else if r.quarantine(blockErr) {
+		return
+	}
+
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, ErrReorgDetected) {
+		log.Warn().Err(err).Str("indexer", r.Name()).Msg("index paused by reorg")
+	} else {
+		log.Error().Err(err).Str("indexer", r.Name()).Msg("index failed")
+	}
+
+	if err := db.UpdateIndexPointError(r.dbOperator, r.Name(), err.Error()); err != nil {
+		log.Error().Err(err).Str("indexer", r.Name()).Msg("record index error failed")
+	}
+}
+
+// quarantine counts the consecutive failures of a block and quarantines it once they exceed the
+// configured retries, the block is then skipped. It reports whether the block was quarantined.
+func (r *Runner) quarantine(blockErr *BlockError) bool {
+	if r.failures > 0 && r.failedHeight == blockErr.Height {
+		r.failures++
+	} else {
+		r.failedHeight, r.failures = blockErr.Height, 1
+	}
+
+	if r.conf.MaxRetries == 0 || r.failures <= r.conf.MaxRetries {
+		return false
+	}
+
+	if err := db.CreateQuarantinedBlock(r.dbOperator, &db.QuarantinedBlock{
+		Indexer:     r.Name(),
+		BlockHeight: blockErr.Height,
+		Event:       blockErr.Event,
+		Error:       blockErr.Err.Error(),
+		Retries:     r.conf.MaxRetries,
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		log.Error().Err(err).Str("indexer", r.Name()).Int64("height", blockErr.Height).Msg("quarantine block failed")
+		return false
+	}
+
+	r.failures = 0
+	metrics.IndexerQuarantinedBlockCounter.WithLabelValues(r.Name()).Inc()
+	log.Error().
+		Err(blockErr.Err).
+		Str("indexer", r.Name()).
+		Int64("height", blockErr.Height).
+		Str("event", blockErr.Event).
+		Msg("block quarantined after too many failures, skipping it")
+
+	return true
+}
+
+func (r *Runner) run(ctx context.Context, chainHead int64) error {
+	if err := db.UpdateIndexPointChainHead(r.dbOperator, r.Name(), chainHead); err != nil {
+		return fmt.Errorf("update chain head failed: %w", err)
+	}
+
+	indexPoint, err := db.GetIndexPoint(r.dbOperator, r.Name())
+	if err != nil {
+		return fmt.Errorf("get index point failed: %w", err)
+	}
+
+	from, to := max(indexPoint.BlockHeight+1, r.conf.StartHeight), chainHead-*r.conf.Confirmations
+
+	if dependent, ok := r.indexer.(DependentIndexer); ok {
+		for _, dep := range dependent.Dependencies() {
+			depIndexPoint, err := db.GetIndexPoint(r.dbOperator, dep)
+			if err != nil {
+				return fmt.Errorf("get index point of dependency %s failed: %w", dep, err)
+			}
+
+			to = min(to, depIndexPoint.BlockHeight)
+		}
+	}
+
+	for start := from; start <= to; start += r.conf.BatchSize {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		end := min(start+r.conf.BatchSize-1, to)
+		if err := r.index(start, end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// index indexes the blocks in [from, to] around the quarantined ones, moving the index point past them.
+func (r *Runner) index(from, to int64) error {
+	quarantined, err := db.GetQuarantinedBlocks(r.dbOperator, r.Name(), from, to)
+	if err != nil {
+		return fmt.Errorf("get quarantined blocks failed: %w", err)
+	}
+
+	start := from
+	for _, q := range quarantined {
+		if start < q.BlockHeight {
+			if err := r.indexer.Index(start, q.BlockHeight-1); err != nil {
+				return fmt.Errorf("index blocks [%d, %d] failed: %w", start, q.BlockHeight-1, err)
+			}
+		}
+
+		if err := db.UpdateIndexPoint(r.dbOperator, r.Name(), q.BlockHeight); err != nil {
+			return fmt.Errorf("skip quarantined block %d failed: %w", q.BlockHeight, err)
+		}
+		start = q.BlockHeight + 1
+	}
+
+	if start <= to {
+		if err := r.indexer.Index(start, to); err != nil {
+			return fmt.Errorf("index blocks [%d, %d] failed: %w", start, to, err)
+		}
+	}
+
+	return nil
+}
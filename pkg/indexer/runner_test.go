@@ -0,0 +1,175 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+// fakeIndexer records the ranges it is asked to index and fails the ranges covering failHeight with a BlockError.
+type fakeIndexer struct {
+	dbOperator *gorm.DB
+
+	dependencies []string
+	failHeight   int64
+
+	ranges [][2]int64
+}
+
+func (f *fakeIndexer) Name() string {
+	return "fake"
+}
+
+func (f *fakeIndexer) ChainHead() (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeIndexer) Dependencies() []string {
+	return f.dependencies
+}
+
+func (f *fakeIndexer) Index(from, to int64) error {
+	if from <= f.failHeight && f.failHeight <= to {
+		return &BlockError{Height: f.failHeight, Event: "log 0", Err: errors.New("malformed event")}
+	}
+
+	f.ranges = append(f.ranges, [2]int64{from, to})
+
+	return db.UpdateIndexPoint(f.dbOperator, f.Name(), to)
+}
+
+// fakeReindexer is a fakeIndexer whose blocks can be quarantined.
+type fakeReindexer struct {
+	*fakeIndexer
+}
+
+func (f *fakeReindexer) Reindex(_, _ int64) error {
+	return nil
+}
+
+func setupRunnerDB(t *testing.T, indexers ...string) *gorm.DB {
+	t.Helper()
+
+	dbOperator, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	dbConn, err := dbOperator.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { dbConn.Close() })
+
+	require.NoError(t, dbOperator.AutoMigrate(&db.IndexPoint{}))
+	require.NoError(t, dbOperator.AutoMigrate(&db.QuarantinedBlock{}))
+
+	for _, name := range indexers {
+		require.NoError(t, db.SetupIndexPoint(dbOperator, &db.IndexPoint{Indexer: name}))
+	}
+
+	return dbOperator
+}
+
+func TestRunnerRun(t *testing.T) {
+	dbOperator := setupRunnerDB(t, "fake", "dep")
+	require.NoError(t, db.UpdateIndexPoint(dbOperator, "dep", 8))
+
+	idx := &fakeIndexer{dbOperator: dbOperator, dependencies: []string{"dep"}}
+	confirmations := int64(2)
+	r := NewRunner(dbOperator, idx, Config{Confirmations: &confirmations, BatchSize: 4})
+
+	// Capped at the index point of the dependency.
+	require.NoError(t, r.run(context.Background(), 20))
+	require.Equal(t, [][2]int64{{1, 4}, {5, 8}}, idx.ranges)
+
+	indexPoint, err := db.GetIndexPoint(dbOperator, "fake")
+	require.NoError(t, err)
+	require.Equal(t, int64(8), indexPoint.BlockHeight)
+	require.Equal(t, int64(20), indexPoint.ChainHead)
+
+	// Kept the confirmations behind the chain head once the dependency is ahead.
+	idx.ranges = nil
+	require.NoError(t, db.UpdateIndexPoint(dbOperator, "dep", 30))
+	require.NoError(t, r.run(context.Background(), 20))
+	require.Equal(t, [][2]int64{{9, 12}, {13, 16}, {17, 18}}, idx.ranges)
+
+	// Nothing to index within the confirmations.
+	idx.ranges = nil
+	require.NoError(t, r.run(context.Background(), 19))
+	require.Empty(t, idx.ranges)
+
+	t.Run("start height", func(t *testing.T) {
+		dbOperator := setupRunnerDB(t, "fake")
+
+		idx := &fakeIndexer{dbOperator: dbOperator}
+		confirmations := int64(0)
+		r := NewRunner(dbOperator, idx, Config{Confirmations: &confirmations, BatchSize: 10, StartHeight: 5})
+
+		require.NoError(t, r.run(context.Background(), 12))
+		require.Equal(t, [][2]int64{{5, 12}}, idx.ranges)
+	})
+}
+
+func TestRunnerQuarantine(t *testing.T) {
+	dbOperator := setupRunnerDB(t, "fake")
+
+	idx := &fakeIndexer{dbOperator: dbOperator, failHeight: 3}
+	confirmations := int64(0)
+	r := NewRunner(dbOperator, &fakeReindexer{idx}, Config{Confirmations: &confirmations, BatchSize: 10, MaxRetries: 2})
+
+	// The block is retried until the failures exceed the retries.
+	for i := 0; i < 2; i++ {
+		r.handleError(r.run(context.Background(), 5))
+
+		quarantined, err := db.GetQuarantinedBlocks(dbOperator, "fake", 1, 5)
+		require.NoError(t, err)
+		require.Empty(t, quarantined)
+
+		indexPoint, err := db.GetIndexPoint(dbOperator, "fake")
+		require.NoError(t, err)
+		require.Contains(t, indexPoint.LastError, "malformed event")
+	}
+
+	r.handleError(r.run(context.Background(), 5))
+
+	quarantined, err := db.GetQuarantinedBlocks(dbOperator, "fake", 1, 5)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(quarantined))
+	require.Equal(t, int64(3), quarantined[0].BlockHeight)
+	require.Equal(t, "log 0", quarantined[0].Event)
+	require.Equal(t, 2, quarantined[0].Retries)
+
+	// The quarantined block is skipped and the index point moves past it.
+	require.NoError(t, r.run(context.Background(), 5))
+	require.Equal(t, [][2]int64{{1, 2}, {4, 5}}, idx.ranges)
+
+	indexPoint, err := db.GetIndexPoint(dbOperator, "fake")
+	require.NoError(t, err)
+	require.Equal(t, int64(5), indexPoint.BlockHeight)
+
+	t.Run("another block resets the failures", func(t *testing.T) {
+		r := NewRunner(dbOperator, &fakeReindexer{idx}, Config{MaxRetries: 1})
+
+		require.False(t, r.quarantine(&BlockError{Height: 7, Err: errors.New("malformed event")}))
+		require.False(t, r.quarantine(&BlockError{Height: 8, Err: errors.New("malformed event")}))
+		require.True(t, r.quarantine(&BlockError{Height: 8, Err: errors.New("malformed event")}))
+	})
+
+	t.Run("not a reindexer", func(t *testing.T) {
+		dbOperator := setupRunnerDB(t, "fake")
+
+		idx := &fakeIndexer{dbOperator: dbOperator, failHeight: 3}
+		r := NewRunner(dbOperator, idx, Config{Confirmations: &confirmations, MaxRetries: 1})
+
+		for i := 0; i < 3; i++ {
+			r.handleError(r.run(context.Background(), 5))
+		}
+
+		quarantined, err := db.GetQuarantinedBlocks(dbOperator, "fake", 1, 5)
+		require.NoError(t, err)
+		require.Empty(t, quarantined)
+	})
+}
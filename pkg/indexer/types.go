@@ -22,3 +22,28 @@ const (
 	WithdrawalTypeReward  = 1
 	WithdrawalTypeUBI     = 2
 )
+
+// Kinds of the withdrawals indexed by the el withdrawal indexer.
+const (
+	WithdrawalKindUnstake = "unstake"
+	WithdrawalKindUBI     = "ubi"
+)
+
+var WithdrawalKinds = []string{WithdrawalKindUnstake, WithdrawalKindUBI}
+
+var withdrawalType2Kind = map[uint64]string{
+	WithdrawalTypeUnstake: WithdrawalKindUnstake,
+	WithdrawalTypeUBI:     WithdrawalKindUBI,
+}
+
+// Kinds of the validator penalties indexed by the cl staking event indexer.
+const (
+	PenaltyKindSlash = "slash"
+	PenaltyKindJail  = "jail"
+)
+
+// Reasons of the validator penalties, as given by the slash and jail events.
+const (
+	PenaltyReasonDoubleSign       = "double_sign"
+	PenaltyReasonMissingSignature = "missing_signature"
+)
@@ -1,13 +1,25 @@
 package indexer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	abcitypes "github.com/cometbft/cometbft/abci/types"
+	comethttp "github.com/cometbft/cometbft/rpc/client/http"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
 	IndexLag = 10
+
+	// MaxReorgDepth is the maximum number of EL blocks that are walked back to find a fork point.
+	MaxReorgDepth = 128
 )
 
+var ErrReorgDetected = errors.New("chain reorganization detected")
+
 func attrArray2Map(attrs []abcitypes.EventAttribute) map[string]string {
 	attrMap := make(map[string]string)
 	for _, attr := range attrs {
@@ -16,3 +28,65 @@ func attrArray2Map(attrs []abcitypes.EventAttribute) map[string]string {
 
 	return attrMap
 }
+
+func clChainHead(ctx context.Context, cometClient *comethttp.HTTP) (int64, error) {
+	latestBlk, err := cometClient.Block(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return latestBlk.Block.Height, nil
+}
+
+func elChainHead(ctx context.Context, ethClient *ethclient.Client) (int64, error) {
+	latestBlkNum, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(latestBlkNum), nil
+}
+
+// fetchBlocks fetches the blocks in [from, to] with at most concurrency requests in flight,
+// the results are returned ordered by height. It fails on the first error.
+func fetchBlocks[T any](ctx context.Context, from, to int64, concurrency int, fetch func(ctx context.Context, height int64) (T, error)) ([]T, error) {
+	results := make([]T, to-from+1)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(concurrency, 1))
+
+	for i := from; i <= to; i++ {
+		g.Go(func() error {
+			res, err := fetch(gctx, i)
+			if err != nil {
+				return err
+			}
+
+			results[i-from] = res
+
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BlockError is returned by indexers when the data of a block cannot be indexed, unlike RPC or
+// database errors it does not go away on retry.
+type BlockError struct {
+	Height int64
+	Event  string
+	Err    error
+}
+
+func (e *BlockError) Error() string {
+	return fmt.Sprintf("block %d: %v", e.Height, e.Err)
+}
+
+func (e *BlockError) Unwrap() error {
+	return e.Err
+}
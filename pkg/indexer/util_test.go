@@ -0,0 +1,31 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchBlocks(t *testing.T) {
+	// The later blocks are fetched faster, the results keep the height order all the same.
+	results, err := fetchBlocks(context.Background(), 10, 29, 4, func(_ context.Context, height int64) (int64, error) {
+		time.Sleep(time.Duration(30-height) * time.Millisecond)
+		return height, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 20, len(results))
+	for i, height := range results {
+		require.Equal(t, int64(10+i), height)
+	}
+
+	_, err = fetchBlocks(context.Background(), 1, 5, 2, func(_ context.Context, height int64) (int64, error) {
+		if height == 3 {
+			return 0, errors.New("rpc unavailable")
+		}
+		return height, nil
+	})
+	require.ErrorContains(t, err, "rpc unavailable")
+}
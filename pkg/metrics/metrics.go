@@ -35,6 +35,36 @@ var (
 		[]string{"method", "path", "status"},
 	)
 
+	WriterLeader = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "staking_api_writer_leader",
+			Help: "Whether the writer holds the leader lock and runs the indexers",
+		},
+	)
+
+	WriterLeaderTransitionCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "staking_api_writer_leader_transitions_total",
+			Help: "Total number of transitions of the writer between the leader and standby roles",
+		},
+		[]string{"role"},
+	)
+
+	IndexerQuarantinedBlockCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "staking_api_indexer_quarantined_blocks_total",
+			Help: "Total number of blocks quarantined by the indexers",
+		},
+		[]string{"indexer"},
+	)
+
+	TotalStakeDrift = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "staking_api_total_stake_drift_gwei",
+			Help: "Bonded tokens of the staking pool plus the tokens of the unbonded validators minus the indexed total stake as of the last reconciliation",
+		},
+	)
+
 	RPCRequestErrorCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "staking_api_story_api_req_errors_total",
@@ -49,6 +79,10 @@ func init() {
 	prometheus.MustRegister(RequestCounter)
 	prometheus.MustRegister(RequestDuration)
 	prometheus.MustRegister(RPCRequestErrorCounter)
+	prometheus.MustRegister(WriterLeader)
+	prometheus.MustRegister(WriterLeaderTransitionCounter)
+	prometheus.MustRegister(IndexerQuarantinedBlockCounter)
+	prometheus.MustRegister(TotalStakeDrift)
 }
 
 func Middleware() gin.HandlerFunc {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	redis "github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
@@ -30,13 +31,17 @@ func GetCachedData[T any](ctx context.Context, rdb *redis.Client, key string) (*
 }
 
 func SetCachedData[T any](ctx context.Context, rdb *redis.Client, key string, data T) bool {
+	return SetCachedDataWithTTL(ctx, rdb, key, data, cache.DefaultCacheTTL)
+}
+
+func SetCachedDataWithTTL[T any](ctx context.Context, rdb *redis.Client, key string, data T, ttl time.Duration) bool {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		log.Error().Err(err).Str("key", key).Msg("failed to marshal data")
 		return false
 	}
 
-	if err := cache.SetRedisData(ctx, rdb, key, string(jsonData)); err != nil {
+	if err := cache.SetRedisDataWithTTL(ctx, rdb, key, string(jsonData), ttl); err != nil {
 		log.Error().Err(err).Str("key", key).Msg("failed to cache data")
 		return false
 	}
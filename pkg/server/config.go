@@ -1,6 +1,12 @@
 package server
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+
+	"github.com/piplabs/story-staking-api/pkg/chain"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
 
 const (
 	IndexModeReader = "reader"
@@ -17,9 +23,11 @@ const (
 
 type Config struct {
 	Blockchain BlockchainConfig `toml:"blockchain"`
+	Chain      chain.Profile    `toml:"chain"`
 	Server     ServerConfig     `toml:"server"`
 	Database   DatabaseConfig   `toml:"database"`
 	Cache      CacheConfig      `toml:"cache"`
+	Indexers   IndexersConfig   `toml:"indexers"`
 }
 
 type BlockchainConfig struct {
@@ -45,6 +53,13 @@ type CacheConfig struct {
 	ConfigFile string `toml:"config_file"`
 }
 
+// IndexersConfig maps indexer names to their configs, indexers absent from it use the defaults.
+type IndexersConfig map[string]indexer.Config
+
+func (c IndexersConfig) Get(name string) indexer.Config {
+	return c[name].WithDefaults()
+}
+
 func (c Config) Validate() error {
 	switch c.Server.IndexMode {
 	case IndexModeReader, IndexModeWriter:
@@ -53,6 +68,10 @@ func (c Config) Validate() error {
 		return fmt.Errorf("invalid index mode: %s", c.Server.IndexMode)
 	}
 
+	if err := c.Chain.Validate(); err != nil {
+		return fmt.Errorf("invalid chain profile: %w", err)
+	}
+
 	switch c.Database.Engine {
 	case DatabaseEnginePostgres:
 		// Valid, do nothing.
@@ -67,5 +86,15 @@ func (c Config) Validate() error {
 		return fmt.Errorf("invalid cache engine: %s", c.Cache.Engine)
 	}
 
+	for name, indexerConf := range c.Indexers {
+		if !slices.Contains(indexer.Names, name) {
+			return fmt.Errorf("unknown indexer: %s", name)
+		}
+
+		if err := indexerConf.Validate(); err != nil {
+			return fmt.Errorf("invalid config of indexer %s: %w", name, err)
+		}
+	}
+
 	return nil
 }
@@ -7,4 +7,5 @@ var (
 	ErrInternalAPIServiceError  = errors.New("internal api service error")
 	ErrParseParameter           = errors.New("parse parameter error")
 	ErrInvalidParameter         = errors.New("invalid parameter")
+	ErrValidatorNotFound        = errors.New("validator not found")
 )
@@ -1,8 +1,11 @@
 package server
 
 import (
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +17,7 @@ import (
 
 	"github.com/piplabs/story-staking-api/cache"
 	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
 	"github.com/piplabs/story-staking-api/pkg/util"
 )
 
@@ -26,6 +30,42 @@ const (
 	IntervalAllTime    Interval = "all"
 )
 
+// StartTime returns the start of the interval ending at now, the zero time for IntervalAllTime.
+// It returns false for unknown intervals.
+func (i Interval) StartTime(now time.Time) (time.Time, bool) {
+	switch i {
+	case IntervalOneDay:
+		return now.AddDate(0, 0, -1), true
+	case IntervalSevenDays:
+		return now.AddDate(0, 0, -7), true
+	case IntervalThirtyDays:
+		return now.AddDate(0, 0, -30), true
+	case IntervalAllTime:
+		return time.Time{}, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+type Granularity string
+
+const (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+)
+
+// Seconds returns the length of a bucket of the granularity, 0 for unknown granularities.
+func (g Granularity) Seconds() int64 {
+	switch g {
+	case GranularityHour:
+		return int64(time.Hour / time.Second)
+	case GranularityDay:
+		return int64(24 * time.Hour / time.Second)
+	default:
+		return 0
+	}
+}
+
 func (s *Server) GetSystemAPRPercentage() (decimal.Decimal, error) {
 	distParamsResp, err := GetDistributionParams(s.conf.Blockchain.StoryAPIEndpoint)
 	if err != nil {
@@ -151,6 +191,92 @@ func (s *Server) NetworkStatusHandler() gin.HandlerFunc {
 	}
 }
 
+func (s *Server) IndexersHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "IndexersHandler").Logger()
+
+		indexPoints, err := db.GetIndexPoints(s.dbOperator)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get index points")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		clHeights := make([]int64, 0, len(indexPoints))
+		for _, ip := range indexPoints {
+			if indexer.Layer(ip.Indexer) == indexer.LayerCL {
+				clHeights = append(clHeights, ip.BlockHeight)
+			}
+		}
+
+		clBlks, err := db.GetCLBlocks(s.dbOperator, clHeights)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get cl blocks")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		clBlkTimes := make(map[int64]time.Time, len(clBlks))
+		for _, blk := range clBlks {
+			clBlkTimes[blk.Height] = blk.Time
+		}
+
+		statuses := make([]IndexerStatus, 0, len(indexPoints))
+		for _, ip := range indexPoints {
+			status := IndexerStatus{
+				Indexer:     ip.Indexer,
+				Layer:       indexer.Layer(ip.Indexer),
+				IndexHeight: ip.BlockHeight,
+				ChainHead:   ip.ChainHead,
+				LagBlocks:   max(ip.ChainHead-ip.BlockHeight, 0),
+				LastError:   ip.LastError,
+			}
+
+			var blkTime time.Time
+			if status.Layer == indexer.LayerCL {
+				blkTime = clBlkTimes[ip.BlockHeight]
+			} else {
+				elBlk, err := db.GetELBlock(s.dbOperator, ip.BlockHeight)
+				if err != nil {
+					logger.Error().Err(err).Msg("failed to get el block")
+					c.JSON(http.StatusOK, Response{
+						Code:  http.StatusInternalServerError,
+						Error: ErrInternalDataServiceError.Error(),
+					})
+					return
+				} else if elBlk != nil {
+					blkTime = elBlk.Time
+				}
+			}
+
+			if !blkTime.IsZero() {
+				status.LagSeconds = max(int64(time.Since(blkTime).Seconds()), 0)
+			}
+			if ip.LastErrorAt != nil {
+				status.LastErrorAt = ip.LastErrorAt.Unix()
+			}
+			if ip.LastSuccessAt != nil {
+				status.LastSuccessAt = ip.LastSuccessAt.Unix()
+			}
+
+			statuses = append(statuses, status)
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: IndexersData{
+				Indexers: statuses,
+			},
+		})
+	}
+}
+
 func (s *Server) EstimatedAPRHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger := log.With().Str("handler", "EstimatedAPRHandler").Logger()
@@ -224,10 +350,49 @@ func (s *Server) OperationsHandler() gin.HandlerFunc {
 			return
 		}
 
+		// Only look up the unbondings if the page has an unstake to attach them to.
+		var unbondings map[string]*UnbondingData
+		for _, op := range operations {
+			if op.EventType != indexer.TypeUnstake || !op.StatusOK {
+				continue
+			}
+
+			data, err := s.getCachedUnbondings(logger, evmAddr)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to get unbondings")
+				c.JSON(http.StatusOK, Response{
+					Code:  http.StatusInternalServerError,
+					Error: ErrInternalDataServiceError.Error(),
+				})
+				return
+			}
+
+			unbondings = make(map[string]*UnbondingData, len(data.Unbondings))
+			for _, u := range data.Unbondings {
+				if u.TxHash != "" {
+					unbondings[u.TxHash] = u
+				}
+			}
+			break
+		}
+
+		operationsData := make([]OperationData, 0, len(operations))
+		for _, op := range operations {
+			var unbonding *UnbondingData
+			if op.EventType == indexer.TypeUnstake && op.StatusOK {
+				unbonding = unbondings[op.TxHash]
+			}
+
+			operationsData = append(operationsData, OperationData{
+				Operation: op,
+				Unbonding: unbonding,
+			})
+		}
+
 		c.JSON(http.StatusOK, Response{
 			Code: http.StatusOK,
 			Msg: OperationsData{
-				Operations: operations,
+				Operations: operationsData,
 				Count:      len(operations),
 				Total:      total,
 			},
@@ -235,6 +400,36 @@ func (s *Server) OperationsHandler() gin.HandlerFunc {
 	}
 }
 
+func (s *Server) UnbondingsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "UnbondingsHandler").Logger()
+
+		evmAddr := strings.ToLower(c.Param("evm_address"))
+		if evmAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		unbondings, err := s.getCachedUnbondings(logger, evmAddr)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get unbondings")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg:  unbondings,
+		})
+	}
+}
+
 func (s *Server) RewardsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger := log.With().Str("handler", "RewardsHandler").Logger()
@@ -294,6 +489,210 @@ func (s *Server) RewardsHandler() gin.HandlerFunc {
 	}
 }
 
+func (s *Server) RewardsHistoryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "RewardsHistoryHandler").Logger()
+
+		evmAddr := strings.ToLower(c.Param("evm_address"))
+		if evmAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		query, err := parseHistoryQuery(c)
+		if err != nil {
+			logger.Error().Err(err).Msg("invalid history query")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		buckets, err := db.GetELRewardBuckets(s.dbOperator, evmAddr, query.since, query.granularity.Seconds())
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get rewards history")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: RewardsHistoryData{
+				Address:        evmAddr,
+				Interval:       string(query.interval),
+				Granularity:    string(query.granularity),
+				RewardsHistory: newAmountBucketData(buckets),
+			},
+		})
+	}
+}
+
+func (s *Server) WithdrawalsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "WithdrawalsHandler").Logger()
+
+		kind := c.Param("kind")
+		evmAddr := strings.ToLower(c.Param("evm_address"))
+		if evmAddr == "" || !slices.Contains(indexer.WithdrawalKinds, kind) {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		// Get from cache
+		cachedMsg, ok := GetCachedData[WithdrawalsData](s.ctx, s.cacheOperator, cache.WithdrawalsKey(kind, evmAddr))
+		if ok {
+			c.JSON(http.StatusOK, Response{
+				Code: http.StatusOK,
+				Msg:  cachedMsg,
+			})
+			return
+		}
+
+		// Get from database
+		withdrawal, err := db.GetELWithdrawal(s.dbOperator, evmAddr, kind)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, Response{
+				Code: http.StatusOK,
+				Msg: WithdrawalsData{
+					Address: evmAddr,
+					Kind:    kind,
+					Amount:  "0",
+				},
+			})
+			return
+		} else if err != nil {
+			logger.Error().Err(err).Msg("failed to get withdrawals")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		msg := WithdrawalsData{
+			Address:          withdrawal.Address,
+			Kind:             withdrawal.Kind,
+			Amount:           withdrawal.Amount,
+			LastUpdateHeight: withdrawal.LastUpdateHeight,
+		}
+
+		// Set to cache
+		_ = SetCachedData(s.ctx, s.cacheOperator, cache.WithdrawalsKey(kind, evmAddr), msg)
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg:  msg,
+		})
+	}
+}
+
+func (s *Server) WithdrawalsHistoryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "WithdrawalsHistoryHandler").Logger()
+
+		kind := c.Param("kind")
+		evmAddr := strings.ToLower(c.Param("evm_address"))
+		if evmAddr == "" || !slices.Contains(indexer.WithdrawalKinds, kind) {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		query, err := parseHistoryQuery(c)
+		if err != nil {
+			logger.Error().Err(err).Msg("invalid history query")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		buckets, err := db.GetELWithdrawalBuckets(s.dbOperator, evmAddr, kind, query.since, query.granularity.Seconds())
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get withdrawals history")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: WithdrawalsHistoryData{
+				Address:            evmAddr,
+				Kind:               kind,
+				Interval:           string(query.interval),
+				Granularity:        string(query.granularity),
+				WithdrawalsHistory: newAmountBucketData(buckets),
+			},
+		})
+	}
+}
+
+type historyQuery struct {
+	interval    Interval
+	granularity Granularity
+	since       int64 // Unix timestamp of the start of the interval, 0 for all time
+}
+
+// parseHistoryQuery parses the interval and granularity query params of the bucketed history endpoints.
+// The granularity defaults to hourly buckets for the last day and daily ones otherwise.
+func parseHistoryQuery(c *gin.Context) (*historyQuery, error) {
+	interval := Interval(c.DefaultQuery("interval", string(IntervalOneDay)))
+	startTime, ok := interval.StartTime(time.Now())
+	if !ok {
+		return nil, fmt.Errorf("invalid interval: %s", interval)
+	}
+
+	granularity := GranularityDay
+	if interval == IntervalOneDay {
+		granularity = GranularityHour
+	}
+	if g := c.Query("granularity"); g != "" {
+		granularity = Granularity(g)
+	}
+
+	if granularity.Seconds() == 0 {
+		return nil, fmt.Errorf("invalid granularity: %s", granularity)
+	}
+
+	query := &historyQuery{
+		interval:    interval,
+		granularity: granularity,
+	}
+	if interval != IntervalAllTime {
+		query.since = startTime.Unix()
+	}
+
+	return query, nil
+}
+
+func newAmountBucketData(buckets []*db.AmountBucket) []AmountBucketData {
+	data := make([]AmountBucketData, 0, len(buckets))
+	for _, bucket := range buckets {
+		data = append(data, AmountBucketData{
+			Amount:  bucket.Amount,
+			StartAt: bucket.BucketTime,
+		})
+	}
+
+	return data
+}
+
 func (s *Server) TotalStakeHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger := log.With().Str("handler", "TotalStakeHandler").Logger()
@@ -308,7 +707,7 @@ func (s *Server) TotalStakeHandler() gin.HandlerFunc {
 			return
 		}
 
-		indexPointTime, err := db.GetIndexPointTime(s.dbOperator, "cl_staking_event")
+		indexPointTime, err := db.GetIndexPointTime(s.dbOperator, indexer.NameCLStakingEvent)
 		if err != nil {
 			logger.Error().Err(err).Msg("failed to get index point time")
 			c.JSON(http.StatusOK, Response{
@@ -337,20 +736,8 @@ func (s *Server) TotalStakeHistoryHandler() gin.HandlerFunc {
 			interval = string(IntervalOneDay)
 		}
 
-		var (
-			startTime   time.Time
-			currentTime = time.Now()
-		)
-		switch Interval(interval) {
-		case IntervalOneDay:
-			startTime = currentTime.AddDate(0, 0, -1)
-		case IntervalSevenDays:
-			startTime = currentTime.AddDate(0, 0, -7)
-		case IntervalThirtyDays:
-			startTime = currentTime.AddDate(0, 0, -30)
-		case IntervalAllTime:
-			// no filter needed
-		default:
+		startTime, ok := Interval(interval).StartTime(time.Now())
+		if !ok {
 			logger.Error().Str("interval", interval).Msg("invalid interval")
 			c.JSON(http.StatusOK, Response{
 				Code:  http.StatusBadRequest,
@@ -420,34 +807,339 @@ func (s *Server) TotalStakeHistoryHandler() gin.HandlerFunc {
 	}
 }
 
-func (s *Server) StakingPoolHandler() gin.HandlerFunc {
+func (s *Server) StakingValidatorStakeHistoryHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger := log.With().Str("handler", "StakingPoolHandler").Logger()
+		logger := log.With().Str("handler", "StakingValidatorStakeHistoryHandler").Logger()
 
-		stakingPoolResp, err := GetStakingPool(s.conf.Blockchain.StoryAPIEndpoint)
-		if err != nil {
-			logger.Error().Err(err).Msg("failed to get staking pool")
+		valAddr := strings.ToLower(c.Param("validator_address"))
+		if valAddr == "" {
 			c.JSON(http.StatusOK, Response{
-				Code:  http.StatusInternalServerError,
-				Error: ErrInternalAPIServiceError.Error(),
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
 			})
 			return
 		}
 
-		c.JSON(http.StatusOK, Response{
-			Code: http.StatusOK,
-			Msg:  stakingPoolResp.Msg,
-		})
-	}
-}
-
-func (s *Server) StakingValidatorsHandler() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		logger := log.With().Str("handler", "StakingValidatorsHandler").Logger()
-
-		params := ParsePaginationParams(c)
-		if status := c.Query("status"); status != "" {
-			params["status"] = status
+		interval := Interval(c.DefaultQuery("interval", string(IntervalOneDay)))
+		startTime, ok := interval.StartTime(time.Now())
+		if !ok {
+			logger.Error().Str("interval", string(interval)).Msg("invalid interval")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		var stakeHistory []StakeAmountData
+		// Get the last amount before the period, the validator has no stake yet if there is none.
+		if interval != IntervalAllTime {
+			row, err := db.GetLatestCLValidatorStakeHistBefore(s.dbOperator, valAddr, startTime.Unix())
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Error().Err(err).Msg("failed to get latest cl validator stake before period")
+				c.JSON(http.StatusOK, Response{
+					Code:  http.StatusInternalServerError,
+					Error: ErrInternalDataServiceError.Error(),
+				})
+				return
+			} else if err == nil {
+				stakeHistory = append(stakeHistory, StakeAmountData{
+					TotalStakeAmount: row.TotalStakeAmount,
+					UpdateAt:         row.UpdatedAtTime,
+				})
+			}
+		}
+
+		// Get all amount updates after the start time, the start of all time is before any block.
+		rows, err := db.GetCLValidatorStakeHistsAfter(s.dbOperator, valAddr, startTime.Unix())
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get cl validator stakes within period")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+		for _, row := range rows {
+			stakeHistory = append(stakeHistory, StakeAmountData{
+				TotalStakeAmount: row.TotalStakeAmount,
+				UpdateAt:         row.UpdatedAtTime,
+			})
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: ValidatorStakeHistoryData{
+				ValidatorAddress:        valAddr,
+				Interval:                string(interval),
+				TotalStakeAmountHistory: stakeHistory,
+			},
+		})
+	}
+}
+
+func (s *Server) ContractParamsHistoryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "ContractParamsHistoryHandler").Logger()
+
+		param := c.Query("param")
+		if param != "" && !slices.Contains(indexer.ContractParams, param) {
+			logger.Error().Str("param", param).Msg("invalid contract param")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		rows, err := db.GetELContractParamHists(s.dbOperator, param)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get contract params history")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		history := make([]ContractParamData, 0, len(rows))
+		current := make(map[string]string)
+		for _, row := range rows {
+			history = append(history, ContractParamData{
+				Param:       row.Param,
+				Value:       row.Value,
+				BlockHeight: row.BlockHeight,
+				TxHash:      row.TxHash,
+				UpdateAt:    row.BlockTime.Unix(),
+			})
+			current[row.Param] = row.Value
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: ContractParamsHistoryData{
+				Current: current,
+				History: history,
+			},
+		})
+	}
+}
+
+func (s *Server) ValidatorsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "ValidatorsHandler").Logger()
+
+		rows, err := db.GetValidators(s.dbOperator)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get validators")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		validators := make([]ValidatorData, 0, len(rows))
+		for _, row := range rows {
+			validators = append(validators, newValidatorData(row))
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: ValidatorsData{
+				Validators: validators,
+			},
+		})
+	}
+}
+
+func (s *Server) ValidatorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "ValidatorHandler").Logger()
+
+		valAddr := strings.ToLower(c.Param("validator_address"))
+		if valAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		validator, err := db.GetValidator(s.dbOperator, valAddr)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusNotFound,
+				Error: ErrValidatorNotFound.Error(),
+			})
+			return
+		} else if err != nil {
+			logger.Error().Err(err).Msg("failed to get validator")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		hists, err := db.GetValidatorCommissionHists(s.dbOperator, valAddr)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get validator commission history")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		commissionHistory := make([]ValidatorCommissionData, 0, len(hists))
+		for _, hist := range hists {
+			commissionHistory = append(commissionHistory, ValidatorCommissionData{
+				CommissionRate: hist.CommissionRate,
+				BlockHeight:    hist.BlockHeight,
+				TxHash:         hist.TxHash,
+				UpdateAt:       hist.BlockTime.Unix(),
+			})
+		}
+
+		penalties, err := db.GetCLValidatorPenalties(s.dbOperator, validator.ConsensusAddress)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get validator penalties")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: ValidatorDetailData{
+				Validator:         newValidatorData(validator),
+				CommissionHistory: commissionHistory,
+				Penalties:         newPenaltyData(penalties),
+			},
+		})
+	}
+}
+
+func (s *Server) PenaltiesHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "PenaltiesHandler").Logger()
+
+		evmAddr := strings.ToLower(c.Param("evm_address"))
+		if evmAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		windows, err := s.getDelegationWindows(evmAddr)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get delegation windows")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		penalties, err := db.GetDelegatorCLValidatorPenalties(s.dbOperator, windows)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get delegator penalties")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: DelegatorPenaltiesData{
+				Address:   evmAddr,
+				Penalties: newPenaltyData(penalties),
+			},
+		})
+	}
+}
+
+func newPenaltyData(penalties []*db.CLValidatorPenaltyDetail) []PenaltyData {
+	data := make([]PenaltyData, 0, len(penalties))
+	for _, p := range penalties {
+		data = append(data, PenaltyData{
+			ValidatorAddress: p.EVMAddress,
+			ConsensusAddress: p.ConsensusAddress,
+			Kind:             p.Kind,
+			Reason:           p.Reason,
+			Power:            p.Power,
+			Amount:           p.Amount,
+			BlockHeight:      p.BlockHeight,
+			PenalizedAt:      p.BlockTime.Unix(),
+		})
+	}
+
+	return data
+}
+
+// consensusAddress returns the consensus address of the validator from its consensus public key.
+func consensusAddress(val ValidatorInfo) (string, error) {
+	pubKey, err := base64.StdEncoding.DecodeString(val.ConsensusPubKey.Value)
+	if err != nil {
+		return "", err
+	}
+
+	return util.CmpPubKeyToCometAddress(pubKey)
+}
+
+func newValidatorData(v *db.Validator) ValidatorData {
+	return ValidatorData{
+		CmpPubkey:               v.CmpPubkey,
+		EVMAddress:              v.EVMAddress,
+		ConsensusAddress:        v.ConsensusAddress,
+		OperatorAddress:         v.OperatorAddress,
+		Moniker:                 v.Moniker,
+		StakeAmount:             v.StakeAmount,
+		SupportTokenType:        v.SupportTokenType,
+		CommissionRate:          v.CommissionRate,
+		MaxCommissionRate:       v.MaxCommissionRate,
+		MaxCommissionChangeRate: v.MaxCommissionChangeRate,
+		BlockHeight:             v.BlockHeight,
+		TxHash:                  v.TxHash,
+		CreateAt:                v.BlockTime.Unix(),
+	}
+}
+
+func (s *Server) StakingPoolHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "StakingPoolHandler").Logger()
+
+		stakingPoolResp, err := GetStakingPool(s.conf.Blockchain.StoryAPIEndpoint)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get staking pool")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalAPIServiceError.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg:  stakingPoolResp.Msg,
+		})
+	}
+}
+
+func (s *Server) StakingValidatorsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "StakingValidatorsHandler").Logger()
+
+		params := ParsePaginationParams(c)
+		if status := c.Query("status"); status != "" {
+			params["status"] = status
 		}
 
 		// Get from cache
@@ -496,14 +1188,34 @@ func (s *Server) StakingValidatorsHandler() gin.HandlerFunc {
 			return
 		}
 
+		slashingParams, err := s.getSlashingParams()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get slashing params")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
 		clUptimesMap := make(map[string]string)
 		for valAddr, votes := range valVotes {
 			clUptimesMap[strings.ToLower(valAddr)] = decimal.NewFromInt(100).
 				Mul(decimal.NewFromInt(votes)).
-				Div(decimal.NewFromInt(util.UptimeWindow)).
+				Div(decimal.NewFromInt(slashingParams.SignedBlocksWindow)).
 				Truncate(2).String() + "%"
 		}
 
+		proposedBlocks, err := s.getProposedBlocks(slashingParams.SignedBlocksWindow, valAddrs...)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get proposed blocks")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
 		validators := make([]StakingValidatorData, 0, len(stakingValidatorsResp.Msg.Validators))
 		for _, val := range stakingValidatorsResp.Msg.Validators {
 			commissionRate, err := decimal.NewFromString(val.Commission.CommissionRates.Rate)
@@ -523,9 +1235,11 @@ func (s *Server) StakingValidatorsHandler() gin.HandlerFunc {
 			}
 
 			validators = append(validators, StakingValidatorData{
-				ValidatorInfo: val,
-				Uptime:        clUptimesMap[strings.ToLower(val.OperatorAddress)],
-				APR:           valAPR.Truncate(2).String() + "%",
+				ValidatorInfo:      val,
+				Uptime:             clUptimesMap[strings.ToLower(val.OperatorAddress)],
+				MinSignedPerWindow: minSignedPercentage(slashingParams),
+				ProposedBlocks:     proposedBlocks[strings.ToLower(val.OperatorAddress)],
+				APR:                valAPR.Truncate(2).String() + "%",
 			})
 		}
 
@@ -590,14 +1304,34 @@ func (s *Server) StakingValidatorHandler() gin.HandlerFunc {
 			return
 		}
 
+		slashingParams, err := s.getSlashingParams()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get slashing params")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
 		clUptimesMap := make(map[string]string)
 		for valAddr, votes := range valVotes {
 			clUptimesMap[strings.ToLower(valAddr)] = decimal.NewFromInt(100).
 				Mul(decimal.NewFromInt(votes)).
-				Div(decimal.NewFromInt(util.UptimeWindow)).
+				Div(decimal.NewFromInt(slashingParams.SignedBlocksWindow)).
 				Truncate(2).String() + "%"
 		}
 
+		proposedBlocks, err := s.getProposedBlocks(slashingParams.SignedBlocksWindow, valAddr)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get proposed blocks")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
 		commissionRate, err := decimal.NewFromString(val.Commission.CommissionRates.Rate)
 		if err != nil {
 			logger.Error().Err(err).Str("validator", val.OperatorAddress).Msg("failed to parse commission rate")
@@ -614,10 +1348,132 @@ func (s *Server) StakingValidatorHandler() gin.HandlerFunc {
 			valAPR = valAPR.Div(decimal.NewFromInt(2))
 		}
 
+		consAddr, err := consensusAddress(val)
+		if err != nil {
+			logger.Error().Err(err).Str("validator", val.OperatorAddress).Msg("failed to derive consensus address")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalAPIServiceError.Error(),
+			})
+			return
+		}
+
+		penalties, err := db.GetCLValidatorPenalties(s.dbOperator, consAddr)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get validator penalties")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
 		msg := StakingValidatorData{
-			ValidatorInfo: val,
-			Uptime:        clUptimesMap[strings.ToLower(val.OperatorAddress)],
-			APR:           valAPR.Truncate(2).String() + "%",
+			ValidatorInfo:      val,
+			Uptime:             clUptimesMap[strings.ToLower(val.OperatorAddress)],
+			MinSignedPerWindow: minSignedPercentage(slashingParams),
+			ProposedBlocks:     proposedBlocks[strings.ToLower(val.OperatorAddress)],
+			APR:                valAPR.Truncate(2).String() + "%",
+			Penalties:          newPenaltyData(penalties),
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg:  msg,
+		})
+	}
+}
+
+// proposalIntervals are the windows the proposal statistics of a validator are computed over.
+var proposalIntervals = []Interval{IntervalOneDay, IntervalSevenDays, IntervalThirtyDays}
+
+func (s *Server) StakingValidatorProposalsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "StakingValidatorProposalsHandler").Logger()
+
+		valAddr := strings.ToLower(c.Param("validator_address"))
+		if valAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		validator, err := db.GetCLValidatorByEVMAddress(s.dbOperator, valAddr)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusNotFound,
+				Error: ErrValidatorNotFound.Error(),
+			})
+			return
+		} else if err != nil {
+			logger.Error().Err(err).Msg("failed to get cl validator")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		activeHeight, totalVotingPower, err := db.GetCLActiveSet(s.dbOperator)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get cl active set")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		// Validators out of the latest active set are not expected to propose.
+		var votingPower int64
+		if validator.LastActiveHeight == activeHeight {
+			votingPower = validator.VotingPower
+		}
+
+		now := time.Now()
+		windows := make([]ProposalWindowData, 0, len(proposalIntervals))
+		for _, interval := range proposalIntervals {
+			startTime, _ := interval.StartTime(now)
+
+			proposed, total, err := db.CountCLBlocks(s.dbOperator, validator.ConsensusAddress, startTime)
+			if err != nil {
+				logger.Error().Err(err).Str("interval", string(interval)).Msg("failed to count cl blocks")
+				c.JSON(http.StatusOK, Response{
+					Code:  http.StatusInternalServerError,
+					Error: ErrInternalDataServiceError.Error(),
+				})
+				return
+			}
+
+			windows = append(windows, ProposalWindowData{
+				Interval:       string(interval),
+				ProposedBlocks: proposed,
+				TotalBlocks:    total,
+				ActualShare:    percentage(proposed, total),
+			})
+		}
+
+		msg := ValidatorProposalsData{
+			ValidatorAddress: valAddr,
+			ConsensusAddress: validator.ConsensusAddress,
+			VotingPower:      votingPower,
+			ExpectedShare:    percentage(votingPower, totalVotingPower),
+			Windows:          windows,
+		}
+
+		lastProposed, err := db.GetLatestProposedCLBlock(s.dbOperator, validator.ConsensusAddress)
+		if err == nil {
+			msg.LastProposedHeight = lastProposed.Height
+			msg.LastProposedAt = lastProposed.Time.Unix()
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error().Err(err).Msg("failed to get latest proposed cl block")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
 		}
 
 		c.JSON(http.StatusOK, Response{
@@ -627,6 +1483,317 @@ func (s *Server) StakingValidatorHandler() gin.HandlerFunc {
 	}
 }
 
+func (s *Server) StakingValidatorUptimeHistoryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "StakingValidatorUptimeHistoryHandler").Logger()
+
+		valAddr := strings.ToLower(c.Param("validator_address"))
+		if valAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		interval := Interval(c.DefaultQuery("interval", string(IntervalThirtyDays)))
+		startTime, ok := interval.StartTime(time.Now())
+		if !ok {
+			logger.Error().Str("interval", string(interval)).Msg("invalid interval")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		// The day the interval starts in is included.
+		var since int64
+		if interval != IntervalAllTime {
+			since = startTime.UTC().Truncate(24 * time.Hour).Unix()
+		}
+
+		uptimes, err := db.GetValidatorUptimeDaily(s.dbOperator, valAddr, since)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get validator uptime daily")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		slashingParams, err := s.getSlashingParams()
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get slashing params")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		var signedBlocks, totalBlocks int64
+		uptimeHistory := make([]UptimeDailyData, 0, len(uptimes))
+		for _, uptime := range uptimes {
+			signedBlocks += uptime.SignedBlocks
+			totalBlocks += uptime.TotalBlocks
+
+			uptimeHistory = append(uptimeHistory, UptimeDailyData{
+				Day:          uptime.Day,
+				SignedBlocks: uptime.SignedBlocks,
+				MissedBlocks: uptime.MissedBlocks,
+				TotalBlocks:  uptime.TotalBlocks,
+				Uptime:       percentage(uptime.SignedBlocks, uptime.TotalBlocks),
+			})
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: ValidatorUptimeHistoryData{
+				ValidatorAddress:   valAddr,
+				Interval:           string(interval),
+				Uptime:             percentage(signedBlocks, totalBlocks),
+				MinSignedPerWindow: minSignedPercentage(slashingParams),
+				UptimeHistory:      uptimeHistory,
+			},
+		})
+	}
+}
+
+func (s *Server) StakingValidatorIncidentsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "StakingValidatorIncidentsHandler").Logger()
+
+		valAddr := strings.ToLower(c.Param("validator_address"))
+		if valAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to parse page")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		perPage, err := strconv.Atoi(c.DefaultQuery("per_page", "100"))
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to parse per_page")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		incidents, total, err := db.GetValidatorIncidents(s.dbOperator, valAddr, page, perPage)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get validator incidents")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		incidentsData := make([]IncidentData, 0, len(incidents))
+		for _, incident := range incidents {
+			incidentsData = append(incidentsData, IncidentData{
+				StartHeight: incident.StartHeight,
+				EndHeight:   incident.EndHeight,
+				BlockCount:  incident.BlockCount,
+				StartAt:     incident.StartTime.Unix(),
+				EndAt:       incident.EndTime.Unix(),
+				Duration:    int64(incident.EndTime.Sub(incident.StartTime).Seconds()),
+				Ongoing:     incident.Ongoing,
+			})
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: ValidatorIncidentsData{
+				ValidatorAddress: valAddr,
+				Incidents:        incidentsData,
+				Count:            len(incidentsData),
+				Total:            total,
+			},
+		})
+	}
+}
+
+func (s *Server) StakingValidatorSetHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "StakingValidatorSetHandler").Logger()
+
+		indexPoint, err := db.GetIndexPoint(s.dbOperator, indexer.NameCLValidatorVote)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get index point")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		// The latest indexed block by default, blocks above it are not known yet.
+		height := indexPoint.BlockHeight
+		if heightStr := c.Query("height"); heightStr != "" {
+			height, err = strconv.ParseInt(heightStr, 10, 64)
+			if err != nil || height <= 0 || height > indexPoint.BlockHeight {
+				logger.Error().Str("height", heightStr).Int64("index_point", indexPoint.BlockHeight).Msg("invalid height")
+				c.JSON(http.StatusOK, Response{
+					Code:  http.StatusBadRequest,
+					Error: ErrInvalidParameter.Error(),
+				})
+				return
+			}
+		}
+
+		snapshots, err := db.GetCLValidatorSet(s.dbOperator, height)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get validator set")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		data := ValidatorSetData{
+			Height:     height,
+			Validators: make([]ValidatorSetMemberData, 0, len(snapshots)),
+		}
+		for _, v := range snapshots {
+			data.TotalVotingPower += v.VotingPower
+		}
+		for _, v := range snapshots {
+			data.SnapshotHeight = v.Height
+			data.SnapshotAt = v.BlockTime.Unix()
+			data.Validators = append(data.Validators, ValidatorSetMemberData{
+				ConsensusAddress: v.ConsensusAddress,
+				EVMAddress:       v.EVMAddress,
+				VotingPower:      v.VotingPower,
+				ProposerPriority: v.ProposerPriority,
+				Share:            percentage(v.VotingPower, data.TotalVotingPower),
+			})
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg:  data,
+		})
+	}
+}
+
+func (s *Server) StakingValidatorVotingPowerHistoryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger := log.With().Str("handler", "StakingValidatorVotingPowerHistoryHandler").Logger()
+
+		valAddr := strings.ToLower(c.Param("validator_address"))
+		if valAddr == "" {
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		interval := Interval(c.DefaultQuery("interval", string(IntervalThirtyDays)))
+		startTime, ok := interval.StartTime(time.Now())
+		if !ok {
+			logger.Error().Str("interval", string(interval)).Msg("invalid interval")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusBadRequest,
+				Error: ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		hists, err := db.GetCLVotingPowerHists(s.dbOperator, valAddr, startTime)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get voting power history")
+			c.JSON(http.StatusOK, Response{
+				Code:  http.StatusInternalServerError,
+				Error: ErrInternalDataServiceError.Error(),
+			})
+			return
+		}
+
+		history := make([]VotingPowerHistData, 0, len(hists))
+		for _, h := range hists {
+			history = append(history, VotingPowerHistData{
+				Height:           h.Height,
+				UpdateAt:         h.BlockTime.Unix(),
+				VotingPower:      h.VotingPower,
+				TotalVotingPower: h.TotalVotingPower,
+				Share:            percentage(h.VotingPower, h.TotalVotingPower),
+			})
+		}
+
+		c.JSON(http.StatusOK, Response{
+			Code: http.StatusOK,
+			Msg: ValidatorVotingPowerHistoryData{
+				ValidatorAddress:   valAddr,
+				Interval:           string(interval),
+				VotingPowerHistory: history,
+			},
+		})
+	}
+}
+
+// getProposedBlocks returns the number of blocks in the uptime window proposed by each of the validators,
+// keyed by EVM address. Validators never seen in the active set are omitted.
+func (s *Server) getProposedBlocks(uptimeWindow int64, valAddrs ...string) (map[string]int64, error) {
+	validators, err := db.GetCLValidatorsByEVMAddress(s.dbOperator, valAddrs...)
+	if err != nil {
+		return nil, err
+	}
+
+	latestBlk, err := db.GetLatestCLBlock(s.dbOperator)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return map[string]int64{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	consAddrs := make([]string, 0, len(validators))
+	for _, v := range validators {
+		consAddrs = append(consAddrs, v.ConsensusAddress)
+	}
+
+	counts, err := db.GetCLProposerCounts(s.dbOperator, latestBlk.Height-uptimeWindow+1, consAddrs...)
+	if err != nil {
+		return nil, err
+	}
+
+	proposedBlocks := make(map[string]int64, len(validators))
+	for evmAddr, v := range validators {
+		proposedBlocks[evmAddr] = counts[v.ConsensusAddress]
+	}
+
+	return proposedBlocks, nil
+}
+
+// percentage formats part/total as a percentage truncated to 2 decimals, "0%" if total is 0.
+func percentage(part, total int64) string {
+	if total == 0 {
+		return "0%"
+	}
+
+	return decimal.NewFromInt(100).
+		Mul(decimal.NewFromInt(part)).
+		Div(decimal.NewFromInt(total)).
+		Truncate(2).String() + "%"
+}
+
 func (s *Server) StakingValidatorDelegationsHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		logger := log.With().Str("handler", "StakingValidatorsHandler").Logger()
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/piplabs/story-staking-api/pkg/metrics"
@@ -17,6 +18,13 @@ const (
 	TokenTypeUnlocked = 1
 )
 
+// BondStatusBonded is the status of the validators in the active set, their tokens make up the bonded tokens of the
+// staking pool.
+const BondStatusBonded = 3
+
+// HeaderQueryHeight selects the block height the story api queries the state at, the latest by default.
+const HeaderQueryHeight = "x-cosmos-block-height"
+
 type QueryResponse[T any] struct {
 	Code  int    `json:"code"`
 	Msg   T      `json:"msg"`
@@ -64,6 +72,16 @@ type StakingParamsResponse struct {
 	} `json:"params"`
 }
 
+type SlashingParamsResponse struct {
+	Params struct {
+		SignedBlocksWindow      string `json:"signed_blocks_window"`
+		MinSignedPerWindow      string `json:"min_signed_per_window"`
+		DowntimeJailDuration    string `json:"downtime_jail_duration"`
+		SlashFractionDoubleSign string `json:"slash_fraction_double_sign"`
+		SlashFractionDowntime   string `json:"slash_fraction_downtime"`
+	} `json:"params"`
+}
+
 type StakingPoolResponse struct {
 	Pool struct {
 		NotBondedTokens string `json:"not_bonded_tokens"`
@@ -241,6 +259,32 @@ func GetMintParams(apiEndpoint string) (*QueryResponse[MintParamsResponse], erro
 	return &res, nil
 }
 
+func GetSlashingParams(apiEndpoint string) (*QueryResponse[SlashingParamsResponse], error) {
+	resp, err := callAPI(apiEndpoint, "/slashing/params", nil)
+	if err != nil {
+		return nil, err
+	} else if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var res QueryResponse[SlashingParamsResponse]
+	if err := json.Unmarshal(bodyBytes, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Code != http.StatusOK {
+		return nil, errors.New(res.Error)
+	}
+
+	return &res, nil
+}
+
 func GetStakingParams(apiEndpoint string) (*QueryResponse[StakingParamsResponse], error) {
 	resp, err := callAPI(apiEndpoint, "/staking/params", nil)
 	if err != nil {
@@ -268,7 +312,16 @@ func GetStakingParams(apiEndpoint string) (*QueryResponse[StakingParamsResponse]
 }
 
 func GetStakingPool(apiEndpoint string) (*QueryResponse[StakingPoolResponse], error) {
-	resp, err := callAPI(apiEndpoint, "/staking/pool", nil)
+	return getStakingPool(apiEndpoint, nil)
+}
+
+// GetStakingPoolAt returns the staking pool as of the block height, the node must keep the state of the height.
+func GetStakingPoolAt(apiEndpoint string, height int64) (*QueryResponse[StakingPoolResponse], error) {
+	return getStakingPool(apiEndpoint, map[string]string{HeaderQueryHeight: strconv.FormatInt(height, 10)})
+}
+
+func getStakingPool(apiEndpoint string, headers map[string]string) (*QueryResponse[StakingPoolResponse], error) {
+	resp, err := callAPIWithHeaders(apiEndpoint, "/staking/pool", nil, headers)
 	if err != nil {
 		return nil, err
 	} else if resp.StatusCode != http.StatusOK {
@@ -294,7 +347,16 @@ func GetStakingPool(apiEndpoint string) (*QueryResponse[StakingPoolResponse], er
 }
 
 func GetStakingValidators(apiEndpoint string, params map[string]string) (*QueryResponse[ValidatorsResponse], error) {
-	resp, err := callAPI(apiEndpoint, "/staking/validators", params)
+	return getStakingValidators(apiEndpoint, params, nil)
+}
+
+// GetStakingValidatorsAt returns the validators as of the block height, the node must keep the state of the height.
+func GetStakingValidatorsAt(apiEndpoint string, params map[string]string, height int64) (*QueryResponse[ValidatorsResponse], error) {
+	return getStakingValidators(apiEndpoint, params, map[string]string{HeaderQueryHeight: strconv.FormatInt(height, 10)})
+}
+
+func getStakingValidators(apiEndpoint string, params, headers map[string]string) (*QueryResponse[ValidatorsResponse], error) {
+	resp, err := callAPIWithHeaders(apiEndpoint, "/staking/validators", params, headers)
 	if err != nil {
 		return nil, err
 	} else if resp.StatusCode != http.StatusOK {
@@ -502,6 +564,10 @@ func GetStakingDelegatorUnbondingDelegations(apiEndpoint, delegatorAddr string,
 }
 
 func callAPI(apiEndpoint, apiURL string, params map[string]string) (*http.Response, error) {
+	return callAPIWithHeaders(apiEndpoint, apiURL, params, nil)
+}
+
+func callAPIWithHeaders(apiEndpoint, apiURL string, params, headers map[string]string) (*http.Response, error) {
 	reqURL, err := buildURL(apiEndpoint, apiURL, params)
 	if err != nil {
 		return nil, err
@@ -511,6 +577,9 @@ func callAPI(apiEndpoint, apiURL string, params map[string]string) (*http.Respon
 	if err != nil {
 		return nil, err
 	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
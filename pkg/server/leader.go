@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/metrics"
+)
+
+const (
+	RoleLeader  = "leader"
+	RoleStandby = "standby"
+
+	// leaderCheckInterval is how often the leader checks its lock and a standby tries to take it over.
+	leaderCheckInterval = 3 * time.Second
+)
+
+// runLeaderElection runs the indexers only while this writer holds the leader lock, so that writers can
+// run as a highly available group where standbys take over when the leader dies.
+func (s *Server) runLeaderElection(lock *db.LeaderLock) {
+	var (
+		stopRunners context.CancelFunc
+		runnersWg   sync.WaitGroup
+	)
+
+	startRunners := func() {
+		ctx, cancel := context.WithCancel(s.ctx)
+		stopRunners = cancel
+
+		for _, runner := range s.runners {
+			runnersWg.Add(1)
+			go func() {
+				defer runnersWg.Done()
+				runner.Run(ctx)
+			}()
+		}
+
+		// The slashing params are shared state, only the leader refreshes them.
+		runnersWg.Add(1)
+		go func() {
+			defer runnersWg.Done()
+			s.runSlashingParamsRefresher(ctx)
+		}()
+
+		metrics.WriterLeader.Set(1)
+		metrics.WriterLeaderTransitionCounter.WithLabelValues(RoleLeader).Inc()
+		log.Info().Msg("acquired leader lock, start indexing")
+	}
+
+	stepDown := func() {
+		stopRunners()
+		stopRunners = nil
+		runnersWg.Wait()
+
+		metrics.WriterLeader.Set(0)
+		metrics.WriterLeaderTransitionCounter.WithLabelValues(RoleStandby).Inc()
+	}
+
+	ticker := time.NewTicker(leaderCheckInterval)
+	defer ticker.Stop()
+
+	log.Info().Msg("waiting for leader lock")
+
+	for {
+		checkCtx, cancel := context.WithTimeout(s.ctx, leaderCheckInterval)
+		if stopRunners == nil {
+			acquired, err := lock.TryAcquire(checkCtx)
+			if err != nil {
+				log.Error().Err(err).Msg("try acquire leader lock failed")
+			} else if acquired {
+				startRunners()
+			}
+		} else if err := lock.Check(checkCtx); err != nil && s.ctx.Err() == nil {
+			log.Error().Err(err).Msg("lost leader lock, stop indexing")
+			stepDown()
+		}
+		cancel()
+
+		select {
+		case <-s.ctx.Done():
+			if stopRunners != nil {
+				stepDown()
+			}
+			_ = lock.Release(context.Background())
+			return
+		case <-ticker.C:
+		}
+	}
+}
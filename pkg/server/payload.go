@@ -12,6 +12,14 @@ const (
 	StatusDown     NetworkStatus = "Down"
 )
 
+type UnbondingState string
+
+const (
+	UnbondingStateUnbonding UnbondingState = "unbonding"
+	UnbondingStateMatured   UnbondingState = "matured"
+	UnbondingStatePaidOut   UnbondingState = "paid_out"
+)
+
 type Response struct {
 	Code  int    `json:"code"`
 	Msg   any    `json:"msg"`
@@ -24,26 +32,95 @@ type NetworkStatusData struct {
 	ELBlockNumber int64         `json:"execution_block_height"`
 }
 
+type IndexerStatus struct {
+	Indexer       string `json:"indexer"`
+	Layer         string `json:"layer"`
+	IndexHeight   int64  `json:"index_height"`
+	ChainHead     int64  `json:"chain_head"`
+	LagBlocks     int64  `json:"lag_blocks"`
+	LagSeconds    int64  `json:"lag_seconds"`
+	LastError     string `json:"last_error"`
+	LastErrorAt   int64  `json:"last_error_at"`
+	LastSuccessAt int64  `json:"last_success_at"`
+}
+
+type IndexersData struct {
+	Indexers []IndexerStatus `json:"indexers"`
+}
+
 type EstimatedAPRData struct {
 	APR string `json:"apr"`
 }
 
+type OperationData struct {
+	*db.Operation
+	Unbonding *UnbondingData `json:"unbonding,omitempty"` // Successful Unstake only
+}
+
 type OperationsData struct {
-	Operations []*db.Operation `json:"operations"`
+	Operations []OperationData `json:"operations"`
 	Count      int             `json:"count"`
 	Total      int64           `json:"total"`
 }
 
+type UnbondingData struct {
+	TxHash           string         `json:"tx_hash"` // Empty if the unstake is not sent by the delegator
+	ValidatorAddress string         `json:"validator_address"`
+	State            UnbondingState `json:"state"`
+	Amount           string         `json:"amount"`
+	CreationHeight   int64          `json:"creation_height"`
+	CreationTime     int64          `json:"creation_time"`
+	CompletionTime   int64          `json:"completion_time"`
+	PaidOutAmount    string         `json:"paid_out_amount"`
+	PaidOutHeight    int64          `json:"paid_out_height"`
+	PaidOutTime      int64          `json:"paid_out_time"`
+}
+
+type UnbondingsData struct {
+	Address    string           `json:"address"`
+	Unbondings []*UnbondingData `json:"unbondings"`
+}
+
 type RewardsData struct {
 	Address          string `json:"address"`
 	Amount           string `json:"amount"`
 	LastUpdateHeight int64  `json:"last_update_height"`
 }
 
+type AmountBucketData struct {
+	Amount  string `json:"amount"`
+	StartAt int64  `json:"start_at"`
+}
+
+type RewardsHistoryData struct {
+	Address        string             `json:"address"`
+	Interval       string             `json:"interval"`
+	Granularity    string             `json:"granularity"`
+	RewardsHistory []AmountBucketData `json:"rewards_history"`
+}
+
+type WithdrawalsData struct {
+	Address          string `json:"address"`
+	Kind             string `json:"kind"`
+	Amount           string `json:"amount"`
+	LastUpdateHeight int64  `json:"last_update_height"`
+}
+
+type WithdrawalsHistoryData struct {
+	Address            string             `json:"address"`
+	Kind               string             `json:"kind"`
+	Interval           string             `json:"interval"`
+	Granularity        string             `json:"granularity"`
+	WithdrawalsHistory []AmountBucketData `json:"withdrawals_history"`
+}
+
 type StakingValidatorData struct {
 	ValidatorInfo
-	Uptime string `json:"uptime"`
-	APR    string `json:"apr"`
+	Uptime             string        `json:"uptime"`
+	MinSignedPerWindow string        `json:"min_signed_per_window"`
+	ProposedBlocks     int64         `json:"proposed_blocks"`
+	APR                string        `json:"apr"`
+	Penalties          []PenaltyData `json:"penalties,omitempty"` // Only on the detail of a validator
 }
 
 type StakingValidatorsData struct {
@@ -51,7 +128,155 @@ type StakingValidatorsData struct {
 	Pagination Pagination             `json:"pagination"`
 }
 
+type ProposalWindowData struct {
+	Interval       string `json:"interval"`
+	ProposedBlocks int64  `json:"proposed_blocks"`
+	TotalBlocks    int64  `json:"total_blocks"`
+	ActualShare    string `json:"actual_share"`
+}
+
+type ValidatorProposalsData struct {
+	ValidatorAddress   string               `json:"validator_address"`
+	ConsensusAddress   string               `json:"consensus_address"`
+	VotingPower        int64                `json:"voting_power"`
+	ExpectedShare      string               `json:"expected_share"`
+	Windows            []ProposalWindowData `json:"windows"`
+	LastProposedHeight int64                `json:"last_proposed_height"`
+	LastProposedAt     int64                `json:"last_proposed_at"`
+}
+
+type UptimeDailyData struct {
+	Day          int64  `json:"day"`
+	SignedBlocks int64  `json:"signed_blocks"`
+	MissedBlocks int64  `json:"missed_blocks"`
+	TotalBlocks  int64  `json:"total_blocks"`
+	Uptime       string `json:"uptime"`
+}
+
+type ValidatorUptimeHistoryData struct {
+	ValidatorAddress   string            `json:"validator_address"`
+	Interval           string            `json:"interval"`
+	Uptime             string            `json:"uptime"`
+	MinSignedPerWindow string            `json:"min_signed_per_window"`
+	UptimeHistory      []UptimeDailyData `json:"uptime_history"`
+}
+
+type IncidentData struct {
+	StartHeight int64 `json:"start_height"`
+	EndHeight   int64 `json:"end_height"`
+	BlockCount  int64 `json:"block_count"`
+	StartAt     int64 `json:"start_at"`
+	EndAt       int64 `json:"end_at"`
+	Duration    int64 `json:"duration"`
+	Ongoing     bool  `json:"ongoing"`
+}
+
+type ValidatorIncidentsData struct {
+	ValidatorAddress string         `json:"validator_address"`
+	Incidents        []IncidentData `json:"incidents"`
+	Count            int            `json:"count"`
+	Total            int64          `json:"total"`
+}
+
+type ValidatorSetMemberData struct {
+	ConsensusAddress string `json:"consensus_address"`
+	EVMAddress       string `json:"evm_address"`
+	VotingPower      int64  `json:"voting_power"`
+	ProposerPriority int64  `json:"proposer_priority"`
+	Share            string `json:"share"`
+}
+
+type ValidatorSetData struct {
+	Height           int64                    `json:"height"`
+	SnapshotHeight   int64                    `json:"snapshot_height"`
+	SnapshotAt       int64                    `json:"snapshot_at"`
+	TotalVotingPower int64                    `json:"total_voting_power"`
+	Validators       []ValidatorSetMemberData `json:"validators"`
+}
+
+type VotingPowerHistData struct {
+	Height           int64  `json:"height"`
+	UpdateAt         int64  `json:"update_at"`
+	VotingPower      int64  `json:"voting_power"`
+	TotalVotingPower int64  `json:"total_voting_power"`
+	Share            string `json:"share"`
+}
+
+type ValidatorVotingPowerHistoryData struct {
+	ValidatorAddress   string                `json:"validator_address"`
+	Interval           string                `json:"interval"`
+	VotingPowerHistory []VotingPowerHistData `json:"voting_power_history"`
+}
+
 type StakeAmountData struct {
 	TotalStakeAmount int64 `json:"total_stake_amount"`
 	UpdateAt         int64 `json:"update_at"`
 }
+
+type ValidatorStakeHistoryData struct {
+	ValidatorAddress        string            `json:"validator_address"`
+	Interval                string            `json:"interval"`
+	TotalStakeAmountHistory []StakeAmountData `json:"total_stake_amount_history"`
+}
+
+type ContractParamData struct {
+	Param       string `json:"param"`
+	Value       string `json:"value"`
+	BlockHeight int64  `json:"block_height"`
+	TxHash      string `json:"tx_hash"`
+	UpdateAt    int64  `json:"update_at"`
+}
+
+type ContractParamsHistoryData struct {
+	Current map[string]string   `json:"current"`
+	History []ContractParamData `json:"contract_params_history"`
+}
+
+type ValidatorData struct {
+	CmpPubkey               string `json:"cmp_pubkey"`
+	EVMAddress              string `json:"evm_address"`
+	ConsensusAddress        string `json:"consensus_address"`
+	OperatorAddress         string `json:"operator_address"`
+	Moniker                 string `json:"moniker"`
+	StakeAmount             string `json:"stake_amount"`
+	SupportTokenType        int    `json:"support_token_type"`
+	CommissionRate          int64  `json:"commission_rate"`
+	MaxCommissionRate       int64  `json:"max_commission_rate"`
+	MaxCommissionChangeRate int64  `json:"max_commission_change_rate"`
+	BlockHeight             int64  `json:"block_height"`
+	TxHash                  string `json:"tx_hash"`
+	CreateAt                int64  `json:"create_at"`
+}
+
+type ValidatorsData struct {
+	Validators []ValidatorData `json:"validators"`
+}
+
+type ValidatorCommissionData struct {
+	CommissionRate int64  `json:"commission_rate"`
+	BlockHeight    int64  `json:"block_height"`
+	TxHash         string `json:"tx_hash"`
+	UpdateAt       int64  `json:"update_at"`
+}
+
+type PenaltyData struct {
+	ValidatorAddress string `json:"validator_address"`
+	ConsensusAddress string `json:"consensus_address"`
+	Kind             string `json:"kind"`
+	Reason           string `json:"reason"`
+	Power            int64  `json:"power"`
+	Amount           string `json:"amount"`
+	BlockHeight      int64  `json:"block_height"`
+	PenalizedAt      int64  `json:"penalized_at"`
+}
+
+type ValidatorDetailData struct {
+	Validator         ValidatorData             `json:"validator"`
+	CommissionHistory []ValidatorCommissionData `json:"commission_history"`
+	Penalties         []PenaltyData             `json:"penalties"`
+}
+
+type DelegatorPenaltiesData struct {
+	Address   string        `json:"address"`
+	Penalties []PenaltyData `json:"penalties"`
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"cmp"
+	"math/big"
+	"slices"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+// getDelegationWindows returns the spans the delegator delegated to each validator over, from the successful stakes,
+// redelegations and unstakes sent by it or on its behalf.
+func (s *Server) getDelegationWindows(delAddr string) ([]*db.DelegationWindow, error) {
+	ops := make([]*db.SucceededOperation, 0)
+	for _, eventType := range []string{indexer.TypeStake, indexer.TypeRedelegate, indexer.TypeUnstake} {
+		sent, err := db.GetSucceededOperations(s.dbOperator, delAddr, eventType)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, sent...)
+	}
+
+	for _, eventType := range []string{indexer.TypeStakeOnBehalf, indexer.TypeRedelegateOnBehalf, indexer.TypeUnstakeOnBehalf} {
+		onBehalf, err := db.GetSucceededOperationsOnBehalf(s.dbOperator, delAddr, eventType)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, onBehalf...)
+	}
+
+	return delegationWindows(ops), nil
+}
+
+// delegationWindows tracks the stake of the operations on each validator, a window opens when the stake becomes
+// positive and ends at the operation taking it all out. The amounts are the ones moved on the consensus layer, a
+// delegation slashed before being taken out in full stays open.
+func delegationWindows(ops []*db.SucceededOperation) []*db.DelegationWindow {
+	slices.SortStableFunc(ops, func(a, b *db.SucceededOperation) int {
+		return cmp.Compare(a.CLBlockHeight, b.CLBlockHeight)
+	})
+
+	stakes := make(map[string]*big.Int)
+	open := make(map[string]*db.DelegationWindow)
+	windows := make([]*db.DelegationWindow, 0)
+
+	move := func(valAddr string, amount *big.Int, height int64) {
+		stake, ok := stakes[valAddr]
+		if !ok {
+			stake = new(big.Int)
+			stakes[valAddr] = stake
+		}
+
+		// Nothing is left to take out once the stake is gone, whatever was slashed or staked out of sight.
+		if stake.Add(stake, amount).Sign() < 0 {
+			stake.SetInt64(0)
+		}
+
+		w, ok := open[valAddr]
+		switch {
+		case !ok && stake.Sign() > 0:
+			w = &db.DelegationWindow{Validator: valAddr, Since: height}
+			open[valAddr] = w
+			windows = append(windows, w)
+		case ok && stake.Sign() <= 0:
+			w.Until = height
+			delete(open, valAddr)
+		}
+	}
+
+	for _, op := range ops {
+		amount := operationAmount(op)
+
+		switch op.EventType {
+		case indexer.TypeStake, indexer.TypeStakeOnBehalf:
+			move(op.DstValidatorAddress, amount, op.CLBlockHeight)
+		case indexer.TypeRedelegate, indexer.TypeRedelegateOnBehalf:
+			move(op.SrcValidatorAddress, new(big.Int).Neg(amount), op.CLBlockHeight)
+			move(op.DstValidatorAddress, amount, op.CLBlockHeight)
+		case indexer.TypeUnstake, indexer.TypeUnstakeOnBehalf:
+			move(op.DstValidatorAddress, new(big.Int).Neg(amount), op.CLBlockHeight)
+		}
+	}
+
+	return windows
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+func TestDelegationWindows(t *testing.T) {
+	op := func(eventType, src, dst, amount string, height int64) *db.SucceededOperation {
+		return &db.SucceededOperation{
+			Operation: db.Operation{
+				EventType:           eventType,
+				SrcValidatorAddress: src,
+				DstValidatorAddress: dst,
+				Amount:              amount,
+			},
+			CLBlockHeight: height,
+		}
+	}
+
+	// Out of execution order, the operations of each type are queried apart.
+	windows := delegationWindows([]*db.SucceededOperation{
+		op(indexer.TypeUnstake, "", "0xval1", "60", 9),
+		op(indexer.TypeStake, "", "0xval1", "100", 2),
+		op(indexer.TypeUnstakeOnBehalf, "", "0xval1", "40", 4),
+		op(indexer.TypeStakeOnBehalf, "", "0xval1", "60", 7),
+		op(indexer.TypeRedelegate, "0xval1", "0xval2", "100", 3),
+		op(indexer.TypeStake, "", "0xval3", "10", 5),
+		op(indexer.TypeUnstake, "", "0xval3", "5", 6),
+	})
+
+	require.Equal(t, []*db.DelegationWindow{
+		{Validator: "0xval1", Since: 2, Until: 3},
+		{Validator: "0xval2", Since: 3},
+		{Validator: "0xval3", Since: 5},
+		{Validator: "0xval1", Since: 7, Until: 9},
+	}, windows)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+// ListQuarantine logs the blocks quarantined by an indexer.
+func ListQuarantine(ctx context.Context, dir string, conf *Config, name string) error {
+	s, err := newCommandServer(ctx, dir, conf)
+	if err != nil {
+		return err
+	}
+	defer s.closeStorage()
+
+	blocks, err := db.GetQuarantinedBlocks(s.dbOperator, name, 0, math.MaxInt64)
+	if err != nil {
+		return fmt.Errorf("get quarantined blocks failed: %w", err)
+	}
+
+	for _, b := range blocks {
+		log.Info().
+			Str("indexer", b.Indexer).
+			Int64("height", b.BlockHeight).
+			Str("event", b.Event).
+			Str("error", b.Error).
+			Time("quarantined_at", b.CreatedAt).
+			Msg("quarantined block")
+	}
+
+	log.Info().Str("indexer", name).Int("count", len(blocks)).Msg("Listed quarantined blocks")
+
+	return nil
+}
+
+// ReplayQuarantine reindexes the blocks quarantined by an indexer in [from, to] and releases the ones
+// indexed successfully. Blocks still failing stay quarantined.
+func ReplayQuarantine(ctx context.Context, dir string, conf *Config, name string, from, to int64) error {
+	s, err := newCommandServer(ctx, dir, conf)
+	if err != nil {
+		return err
+	}
+	defer s.closeStorage()
+
+	reindexer, err := s.reindexer(name)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := db.GetQuarantinedBlocks(s.dbOperator, name, from, to)
+	if err != nil {
+		return fmt.Errorf("get quarantined blocks failed: %w", err)
+	}
+
+	var failed int
+	for _, b := range blocks {
+		if err := reindexer.Reindex(b.BlockHeight, b.BlockHeight); err != nil {
+			log.Error().Err(err).Str("indexer", name).Int64("height", b.BlockHeight).Msg("replay quarantined block failed")
+			failed++
+			continue
+		}
+
+		if err := db.DeleteQuarantinedBlock(s.dbOperator, name, b.BlockHeight); err != nil {
+			return fmt.Errorf("release quarantined block %d failed: %w", b.BlockHeight, err)
+		}
+
+		log.Info().Str("indexer", name).Int64("height", b.BlockHeight).Msg("replayed quarantined block")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d quarantined blocks failed to replay", failed, len(blocks))
+	}
+
+	log.Info().Str("indexer", name).Int("count", len(blocks)).Msg("Replayed quarantined blocks")
+
+	return nil
+}
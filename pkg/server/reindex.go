@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+// Reindex deletes and rebuilds the data of a single indexer for the blocks in [from, to]. The range
+// must already be indexed, the index points are left untouched so it can run next to a live writer.
+func Reindex(ctx context.Context, dir string, conf *Config, name string, from, to int64) error {
+	if from < 0 || from > to {
+		return fmt.Errorf("invalid range [%d, %d]", from, to)
+	}
+
+	s, err := newCommandServer(ctx, dir, conf)
+	if err != nil {
+		return err
+	}
+	defer s.closeStorage()
+
+	reindexer, err := s.reindexer(name)
+	if err != nil {
+		return err
+	}
+
+	indexPoint, err := db.GetIndexPoint(s.dbOperator, name)
+	if err != nil {
+		return fmt.Errorf("get index point failed: %w", err)
+	}
+
+	if to > indexPoint.BlockHeight {
+		return fmt.Errorf("blocks above the index point %d are not indexed yet", indexPoint.BlockHeight)
+	}
+
+	log.Info().Str("indexer", name).Int64("from", from).Int64("to", to).Msg("Start reindexing")
+
+	if err := reindexer.Reindex(from, to); err != nil {
+		return fmt.Errorf("reindex blocks [%d, %d] failed: %w", from, to, err)
+	}
+
+	log.Info().Str("indexer", name).Int64("from", from).Int64("to", to).Msg("Reindexing finished")
+
+	return nil
+}
+
+// newCommandServer returns a server with its storage and indexers set up, for one-off commands.
+func newCommandServer(ctx context.Context, dir string, conf *Config) (*Server, error) {
+	s := &Server{
+		ctx:  ctx,
+		conf: conf,
+
+		rootDir: dir,
+	}
+
+	if err := s.loadChainProfile(); err != nil {
+		return nil, err
+	}
+
+	if err := s.connectStorage(); err != nil {
+		return nil, err
+	}
+
+	if err := s.migrate(); err != nil {
+		_ = s.closeStorage()
+		return nil, err
+	}
+
+	if err := s.setupIndexers(); err != nil {
+		_ = s.closeStorage()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *Server) reindexer(name string) (indexer.Reindexer, error) {
+	for _, idx := range s.indexers {
+		if idx.Name() != name {
+			continue
+		}
+
+		reindexer, ok := idx.(indexer.Reindexer)
+		if !ok {
+			return nil, fmt.Errorf("indexer %s does not support reindexing", name)
+		}
+
+		return reindexer, nil
+	}
+
+	return nil, fmt.Errorf("unknown indexer: %s", name)
+}
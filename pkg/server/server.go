@@ -3,8 +3,10 @@ package server
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"sync"
 
 	"github.com/gin-contrib/cors"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/piplabs/story-staking-api/cache"
 	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/chain"
 	"github.com/piplabs/story-staking-api/pkg/indexer"
 	"github.com/piplabs/story-staking-api/pkg/metrics"
 )
@@ -28,6 +31,7 @@ type Server struct {
 	sf     *singleflight.Group // TODO: use singleflight for all database queries
 
 	rootDir string
+	chain   chain.Profile
 
 	dbOperator    *gorm.DB
 	cacheOperator *redis.Client
@@ -35,6 +39,8 @@ type Server struct {
 	ginService *gin.Engine
 	httpServer *http.Server
 	indexers   []indexer.Indexer
+	runners    []*indexer.Runner
+	leaderLock *db.LeaderLock
 }
 
 func NewServer(ctx context.Context, dir string, conf *Config) (*Server, error) {
@@ -71,13 +77,12 @@ func (s *Server) Run() {
 	case IndexModeReader:
 		log.Info().Str("port", s.conf.Server.ServicePort).Msg("story-staking-api reader server started")
 	case IndexModeWriter:
-		for _, indexer := range s.indexers {
-			s.wg.Add(1)
-			go func() {
-				defer s.wg.Done()
-				indexer.Run()
-			}()
-		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runLeaderElection(s.leaderLock)
+		}()
+
 		log.Info().Msg("story-staking-api writer process started")
 	default:
 		log.Fatal().Str("index_mode", s.conf.Server.IndexMode).Msg("invalid index mode")
@@ -89,17 +94,65 @@ func (s *Server) GracefulQuit() error {
 	_ = s.httpServer.Shutdown(context.Background())
 	s.wg.Wait()
 
-	_ = s.cacheOperator.Close()
-	connPool, err := s.dbOperator.DB()
-	if err != nil {
+	return s.closeStorage()
+}
+
+func (s *Server) initServices() error {
+	if err := s.loadChainProfile(); err != nil {
 		return err
 	}
-	_ = connPool.Close()
+
+	if err := s.connectStorage(); err != nil {
+		return err
+	}
+
+	// Setup gin service engine.
+	s.setupGinService()
+	if s.conf.Server.IndexMode == IndexModeReader {
+		s.setupStakingAPI()
+	}
+	s.setupHealthCheckAPI()
+	s.setupIndexerStatusAPI()
+
+	// Setup database states and indexers for `writer` mode.
+	if s.conf.Server.IndexMode == IndexModeWriter {
+		if err := s.migrate(); err != nil {
+			return err
+		}
+
+		if err := s.setupIndexers(); err != nil {
+			return err
+		}
+
+		// Initialize genesis index points and runners of the enabled indexers.
+		for _, idx := range s.indexers {
+			indexerConf := s.conf.Indexers.Get(idx.Name())
+			if !indexerConf.IsEnabled() {
+				log.Info().Str("indexer", idx.Name()).Msg("indexer disabled")
+				continue
+			}
+
+			if err := db.SetupIndexPoint(s.dbOperator, &db.IndexPoint{
+				Indexer:     idx.Name(),
+				BlockHeight: max(indexerConf.StartHeight-1, 0),
+			}); err != nil {
+				return err
+			}
+
+			s.runners = append(s.runners, indexer.NewRunner(s.dbOperator, idx, indexerConf))
+		}
+
+		leaderLock, err := db.NewLeaderLock(s.dbOperator, db.LeaderLockKey)
+		if err != nil {
+			return err
+		}
+		s.leaderLock = leaderLock
+	}
 
 	return nil
 }
 
-func (s *Server) initServices() error { // TODO: get pwd from secret manager
+func (s *Server) connectStorage() error { // TODO: get pwd from secret manager
 	// Connect to database.
 	var postgresConfig string
 	if s.conf.Database.ConfigFile != "" {
@@ -122,39 +175,62 @@ func (s *Server) initServices() error { // TODO: get pwd from secret manager
 	}
 	s.cacheOperator = redisClient
 
-	// Setup gin service engine.
-	s.setupGinService()
-	if s.conf.Server.IndexMode == IndexModeReader {
-		s.setupStakingAPI()
+	return nil
+}
+
+func (s *Server) closeStorage() error {
+	_ = s.cacheOperator.Close()
+	connPool, err := s.dbOperator.DB()
+	if err != nil {
+		return err
 	}
-	s.setupHealthCheckAPI()
+	_ = connPool.Close()
 
-	// Setup database states and indexers for `writer` mode.
-	if s.conf.Server.IndexMode == IndexModeWriter {
-		s.dbOperator.AutoMigrate(&db.CLBlock{})
-		s.dbOperator.AutoMigrate(&db.CLStakingEvent{})
-		s.dbOperator.AutoMigrate(&db.CLValidatorVote{})
-		s.dbOperator.AutoMigrate(&db.CLTotalStakeHist{})
-		s.dbOperator.AutoMigrate(&db.ELBlock{})
-		s.dbOperator.AutoMigrate(&db.ELReward{})
-		s.dbOperator.AutoMigrate(&db.ELStakingEvent{})
-		s.dbOperator.AutoMigrate(&db.IndexPoint{})
+	return nil
+}
 
-		if err := s.setupIndexers(); err != nil {
-			return err
-		}
+func (s *Server) migrate() error {
+	if err := db.MigrateStakingEventKeys(s.dbOperator, indexer.NameCLTotalStakeHist, indexer.NameCLValidatorStakeHist); err != nil {
+		return err
+	}
 
-		// Initialize genesis index points.
-		for _, indexer := range s.indexers {
-			if err := db.SetupIndexPoint(s.dbOperator, &db.IndexPoint{
-				Indexer:     indexer.Name(),
-				BlockHeight: 0,
-			}); err != nil {
-				return err
-			}
-		}
+	if err := db.MigrateELRewardDeltaStart(s.dbOperator, indexer.NameELReward); err != nil {
+		return err
+	}
+
+	if err := db.MigrateELRewardDeltaBlockTime(s.dbOperator); err != nil {
+		return err
+	}
+
+	if err := db.MigrateValidatorUptimeDaily(s.dbOperator, indexer.NameCLValidatorVote); err != nil {
+		return err
 	}
 
+	s.dbOperator.AutoMigrate(&db.CLBlock{})
+	s.dbOperator.AutoMigrate(&db.CLStakingEvent{})
+	s.dbOperator.AutoMigrate(&db.CLValidatorPenalty{})
+	s.dbOperator.AutoMigrate(&db.CLValidatorVote{})
+	s.dbOperator.AutoMigrate(&db.CLValidator{})
+	s.dbOperator.AutoMigrate(&db.CLValidatorSetSnapshot{})
+	s.dbOperator.AutoMigrate(&db.ValidatorUptimeDaily{})
+	s.dbOperator.AutoMigrate(&db.ValidatorIncident{})
+	s.dbOperator.AutoMigrate(&db.CLTotalStakeHist{})
+	s.dbOperator.AutoMigrate(&db.CLTotalStakeDrift{})
+	s.dbOperator.AutoMigrate(&db.CLSlashingParams{})
+	s.dbOperator.AutoMigrate(&db.CLValidatorStakeHist{})
+	s.dbOperator.AutoMigrate(&db.ELBlock{})
+	s.dbOperator.AutoMigrate(&db.ELReward{})
+	s.dbOperator.AutoMigrate(&db.ELRewardDelta{})
+	s.dbOperator.AutoMigrate(&db.ELRewardDeltaStart{})
+	s.dbOperator.AutoMigrate(&db.ELWithdrawal{})
+	s.dbOperator.AutoMigrate(&db.ELWithdrawalDelta{})
+	s.dbOperator.AutoMigrate(&db.ELStakingEvent{})
+	s.dbOperator.AutoMigrate(&db.ELContractParamHist{})
+	s.dbOperator.AutoMigrate(&db.Validator{})
+	s.dbOperator.AutoMigrate(&db.ValidatorCommissionHist{})
+	s.dbOperator.AutoMigrate(&db.IndexPoint{})
+	s.dbOperator.AutoMigrate(&db.QuarantinedBlock{})
+
 	return nil
 }
 
@@ -187,16 +263,30 @@ func (s *Server) setupStakingAPI() {
 		apiGroup.GET("/network_status", s.NetworkStatusHandler())
 		apiGroup.GET("/estimated_apr", s.EstimatedAPRHandler())
 		apiGroup.GET("/operations/:evm_address", s.OperationsHandler())
+		apiGroup.GET("/unbondings/:evm_address", s.UnbondingsHandler())
+		apiGroup.GET("/penalties/:evm_address", s.PenaltiesHandler())
 		apiGroup.GET("/rewards/:evm_address", s.RewardsHandler())
+		apiGroup.GET("/rewards/:evm_address/history", s.RewardsHistoryHandler())
+		apiGroup.GET("/withdrawals/:kind/:evm_address", s.WithdrawalsHandler())
+		apiGroup.GET("/withdrawals/:kind/:evm_address/history", s.WithdrawalsHistoryHandler())
 		apiGroup.GET("/staking/total_stake", s.TotalStakeHandler())
 		apiGroup.GET("/staking/total_stake/history", s.TotalStakeHistoryHandler())
+		apiGroup.GET("/staking/contract_params/history", s.ContractParamsHistoryHandler())
+		apiGroup.GET("/validators", s.ValidatorsHandler())
+		apiGroup.GET("/validators/:validator_address", s.ValidatorHandler())
 		// Proxy to Story API.
 		apiGroup.GET("/staking/params", s.StakingParamsHandler())
 
 		apiGroup.GET("/staking/pool", s.StakingPoolHandler())
 
+		apiGroup.GET("/staking/validator_set", s.StakingValidatorSetHandler())
 		apiGroup.GET("/staking/validators", s.StakingValidatorsHandler())
 		apiGroup.GET("/staking/validators/:validator_address", s.StakingValidatorHandler())
+		apiGroup.GET("/staking/validators/:validator_address/proposals", s.StakingValidatorProposalsHandler())
+		apiGroup.GET("/staking/validators/:validator_address/uptime/history", s.StakingValidatorUptimeHistoryHandler())
+		apiGroup.GET("/staking/validators/:validator_address/incidents", s.StakingValidatorIncidentsHandler())
+		apiGroup.GET("/staking/validators/:validator_address/voting_power/history", s.StakingValidatorVotingPowerHistoryHandler())
+		apiGroup.GET("/staking/validators/:validator_address/stake/history", s.StakingValidatorStakeHistoryHandler())
 		apiGroup.GET("/staking/validators/:validator_address/delegations", s.StakingValidatorDelegationsHandler())
 		apiGroup.GET("/staking/validators/:validator_address/delegations/:delegator_address", s.StakingDelegationHandler())
 		apiGroup.GET("/staking/validators/:validator_address/delegators/:delegator_address/period_delegations", s.StakingValidatorDelegatorPeriodDelegationsHandler())
@@ -208,6 +298,11 @@ func (s *Server) setupStakingAPI() {
 	}
 }
 
+// setupIndexerStatusAPI registers the indexer status endpoint, served in both reader and writer mode.
+func (s *Server) setupIndexerStatusAPI() {
+	s.ginService.GET("/api/indexers", s.IndexersHandler())
+}
+
 func (s *Server) setupHealthCheckAPI() {
 	s.ginService.GET("/healthz", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -238,48 +333,133 @@ func (s *Server) setupMonitoringEndpoint() {
 	s.ginService.GET("/metrics", metrics.Handler())
 }
 
+// loadChainProfile completes the configured chain profile from its genesis file and the defaults.
+func (s *Server) loadChainProfile() error {
+	profile, err := s.conf.Chain.Load(s.rootDir)
+	if err != nil {
+		return err
+	}
+	s.chain = profile
+
+	log.Info().
+		Str("chain", profile.Name).
+		Time("genesis_time", profile.GenesisTime).
+		Int64("genesis_block_height", profile.GenesisBlockHeight).
+		Int64("genesis_stake_amount", profile.GenesisStakeAmount).
+		Str("staking_contract_address", profile.StakingContractAddress).
+		Int64("uptime_window", profile.UptimeWindow).
+		Bool("empty_stake_amounts", profile.Quirks.AllowEmptyStakeAmounts()).
+		Msg("chain profile loaded")
+
+	return nil
+}
+
+// fetchStakingPool returns the bonded tokens of the staking pool in gwei as of the block height, along with the
+// tokens of the validators not bonded.
+func (s *Server) fetchStakingPool(height int64) (*indexer.StakingPool, error) {
+	poolResp, err := GetStakingPoolAt(s.conf.Blockchain.StoryAPIEndpoint, height)
+	if err != nil {
+		return nil, err
+	}
+
+	bondedTokens, err := strconv.ParseInt(poolResp.Msg.Pool.BondedTokens, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parse bonded tokens failed: %w", err)
+	}
+
+	pool := &indexer.StakingPool{BondedTokens: bondedTokens}
+	params := map[string]string{}
+	for {
+		validatorsResp, err := GetStakingValidatorsAt(s.conf.Blockchain.StoryAPIEndpoint, params, height)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range validatorsResp.Msg.Validators {
+			if v.Status == BondStatusBonded {
+				continue
+			}
+
+			tokens, err := strconv.ParseInt(v.Tokens, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse tokens of validator %s failed: %w", v.OperatorAddress, err)
+			}
+			pool.UnbondedValidatorTokens += tokens
+		}
+
+		if validatorsResp.Msg.Pagination.NextKey == "" {
+			return pool, nil
+		}
+		params["pagination.key"] = validatorsResp.Msg.Pagination.NextKey
+	}
+}
+
 func (s *Server) setupIndexers() error {
-	clBlockIndexer, err := indexer.NewCLBlockIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.CometbftRPCEndpoint)
+	clBlockIndexer, err := indexer.NewCLBlockIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.CometbftRPCEndpoint, s.conf.Indexers.Get(indexer.NameCLBlock).FetchConcurrency)
 	if err != nil {
 		return err
 	}
 	s.indexers = append(s.indexers, clBlockIndexer)
 
-	clStakingEventIndexer, err := indexer.NewCLStakingEventIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.CometbftRPCEndpoint)
+	clStakingEventIndexer, err := indexer.NewCLStakingEventIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.CometbftRPCEndpoint, s.conf.Indexers.Get(indexer.NameCLStakingEvent).FetchConcurrency)
 	if err != nil {
 		return err
 	}
 	s.indexers = append(s.indexers, clStakingEventIndexer)
 
-	clValidatorVoteIndexer, err := indexer.NewCLValidatorVoteIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.CometbftRPCEndpoint)
+	clValidatorVoteIndexer, err := indexer.NewCLValidatorVoteIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.CometbftRPCEndpoint, s.uptimeWindow)
 	if err != nil {
 		return err
 	}
 	s.indexers = append(s.indexers, clValidatorVoteIndexer)
 
-	clTotalStakeHistIndexer, err := indexer.NewCLTotalStakeHistIndexer(s.ctx, s.dbOperator)
+	clTotalStakeHistIndexer, err := indexer.NewCLTotalStakeHistIndexer(s.ctx, s.dbOperator, s.conf.Blockchain.CometbftRPCEndpoint, s.conf.Indexers.Get(indexer.NameCLTotalStakeHist), s.chain, s.fetchStakingPool)
 	if err != nil {
 		return err
 	}
 	s.indexers = append(s.indexers, clTotalStakeHistIndexer)
 
-	elBlockIndexer, err := indexer.NewELBlockIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint)
+	clValidatorStakeHistIndexer, err := indexer.NewCLValidatorStakeHistIndexer(s.ctx, s.dbOperator, s.conf.Blockchain.CometbftRPCEndpoint, s.chain)
+	if err != nil {
+		return err
+	}
+	s.indexers = append(s.indexers, clValidatorStakeHistIndexer)
+
+	elBlockIndexer, err := indexer.NewELBlockIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint, s.conf.Indexers.Get(indexer.NameELBlock).FetchConcurrency)
 	if err != nil {
 		return err
 	}
 	s.indexers = append(s.indexers, elBlockIndexer)
 
-	elRewardIndexer, err := indexer.NewELRewardIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint)
+	elRewardIndexer, err := indexer.NewELRewardIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint, s.conf.Indexers.Get(indexer.NameELReward).FetchConcurrency)
 	if err != nil {
 		return err
 	}
 	s.indexers = append(s.indexers, elRewardIndexer)
 
-	elStakingEventIndexer, err := indexer.NewELStakingEventIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint)
+	elStakingEventIndexer, err := indexer.NewELStakingEventIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint, s.chain.ContractAddress())
 	if err != nil {
 		return err
 	}
 	s.indexers = append(s.indexers, elStakingEventIndexer)
 
+	elContractParamIndexer, err := indexer.NewELContractParamIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint, s.chain.ContractAddress())
+	if err != nil {
+		return err
+	}
+	s.indexers = append(s.indexers, elContractParamIndexer)
+
+	elValidatorIndexer, err := indexer.NewELValidatorIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint, s.chain.ContractAddress())
+	if err != nil {
+		return err
+	}
+	s.indexers = append(s.indexers, elValidatorIndexer)
+
+	elWithdrawalIndexer, err := indexer.NewELWithdrawalIndexer(s.ctx, s.dbOperator, s.cacheOperator, s.conf.Blockchain.GethRPCEndpoint, s.conf.Indexers.Get(indexer.NameELWithdrawal).FetchConcurrency)
+	if err != nil {
+		return err
+	}
+	s.indexers = append(s.indexers, elWithdrawalIndexer)
+
 	return nil
 }
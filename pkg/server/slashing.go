@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+// slashingParamsRefreshInterval is how often the leader writer refreshes the slashing params from the story api.
+const slashingParamsRefreshInterval = 10 * time.Minute
+
+// runSlashingParamsRefresher keeps the stored slashing params in line with the chain, the uptime window of the
+// validator votes follows their `signed_blocks_window`.
+func (s *Server) runSlashingParamsRefresher(ctx context.Context) {
+	ticker := time.NewTicker(slashingParamsRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.refreshSlashingParams(); err != nil {
+			log.Error().Err(err).Msg("refresh slashing params failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) refreshSlashingParams() error {
+	slashingParamsResp, err := GetSlashingParams(s.conf.Blockchain.StoryAPIEndpoint)
+	if err != nil {
+		return fmt.Errorf("get slashing params failed: %w", err)
+	}
+
+	params := slashingParamsResp.Msg.Params
+	signedBlocksWindow, err := strconv.ParseInt(params.SignedBlocksWindow, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse signed blocks window failed: %w", err)
+	} else if signedBlocksWindow <= 0 {
+		return fmt.Errorf("invalid signed blocks window: %d", signedBlocksWindow)
+	}
+
+	prevWindow, err := s.uptimeWindow()
+	if err != nil {
+		return err
+	}
+
+	if signedBlocksWindow != prevWindow {
+		log.Info().
+			Int64("prev_uptime_window", prevWindow).
+			Int64("uptime_window", signedBlocksWindow).
+			Msg("uptime window resized to the signed blocks window")
+	}
+
+	return db.UpsertCLSlashingParams(s.dbOperator, &db.CLSlashingParams{
+		SignedBlocksWindow:      signedBlocksWindow,
+		MinSignedPerWindow:      params.MinSignedPerWindow,
+		DowntimeJailDuration:    params.DowntimeJailDuration,
+		SlashFractionDoubleSign: params.SlashFractionDoubleSign,
+		SlashFractionDowntime:   params.SlashFractionDowntime,
+		RefreshedAt:             time.Now().Unix(),
+	})
+}
+
+// getSlashingParams returns the slashing params last refreshed by the leader writer. Until the first refresh, only the
+// signed blocks window is known, from the uptime window of the chain profile.
+func (s *Server) getSlashingParams() (*db.CLSlashingParams, error) {
+	params, err := db.GetCLSlashingParams(s.dbOperator)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &db.CLSlashingParams{SignedBlocksWindow: s.chain.UptimeWindow}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return params, nil
+}
+
+// uptimeWindow returns the number of the latest blocks the uptime of validators is measured over.
+func (s *Server) uptimeWindow() (int64, error) {
+	params, err := s.getSlashingParams()
+	if err != nil {
+		return 0, fmt.Errorf("get slashing params failed: %w", err)
+	}
+
+	return params.SignedBlocksWindow, nil
+}
+
+// minSignedPercentage returns the minimum share of the window a validator must sign to stay out of jail, empty
+// if the slashing params were never refreshed.
+func minSignedPercentage(params *db.CLSlashingParams) string {
+	minSigned, err := decimal.NewFromString(params.MinSignedPerWindow)
+	if err != nil {
+		return ""
+	}
+
+	return decimal.NewFromInt(100).Mul(minSigned).Truncate(2).String() + "%"
+}
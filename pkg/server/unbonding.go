@@ -0,0 +1,362 @@
+package server
+
+import (
+	"cmp"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+
+	"github.com/piplabs/story-staking-api/cache"
+	"github.com/piplabs/story-staking-api/db"
+	"github.com/piplabs/story-staking-api/pkg/indexer"
+)
+
+var gweiPerWei = big.NewInt(1e9)
+
+// unbonding tracks the payout of an unbonding entry while withdrawals are matched against it.
+type unbonding struct {
+	data       *UnbondingData
+	amount     *big.Int
+	paid       *big.Int
+	payoutAddr string // Withdrawal address in effect at the completion
+	matched    bool   // Whether an unbonding entry of the story api is matched
+}
+
+// getCachedUnbondings returns the unbondings of the delegator, cached for a short while as they are partly read
+// from the story api.
+func (s *Server) getCachedUnbondings(logger zerolog.Logger, delAddr string) (*UnbondingsData, error) {
+	if cachedMsg, ok := GetCachedData[UnbondingsData](s.ctx, s.cacheOperator, cache.UnbondingsKey(delAddr)); ok {
+		return cachedMsg, nil
+	}
+
+	unbondings, err := s.getUnbondings(logger, delAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &UnbondingsData{
+		Address:    delAddr,
+		Unbondings: unbondings,
+	}
+	_ = SetCachedDataWithTTL(s.ctx, s.cacheOperator, cache.UnbondingsKey(delAddr), msg, cache.UnbondingsCacheTTL)
+
+	return msg, nil
+}
+
+// getUnbondings builds the lifecycle of the unbondings of the delegator, from the successful unstakes sent by it or
+// on its behalf and the unbonding entries still held by the consensus layer to the unstake withdrawals paying the
+// principal. The story api is only used to refine the data, the indexed unstakes are returned if it is unavailable.
+func (s *Server) getUnbondings(logger zerolog.Logger, delAddr string) ([]*UnbondingData, error) {
+	unstakes, err := db.GetSucceededOperations(s.dbOperator, delAddr, indexer.TypeUnstake)
+	if err != nil {
+		return nil, err
+	}
+
+	onBehalfUnstakes, err := db.GetSucceededOperationsOnBehalf(s.dbOperator, delAddr, indexer.TypeUnstakeOnBehalf)
+	if err != nil {
+		return nil, err
+	}
+	unstakes = append(unstakes, onBehalfUnstakes...)
+
+	withdrawalAddrSets, err := db.GetSucceededOperations(s.dbOperator, delAddr, indexer.TypeSetWithdrawalAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	payoutAddrs := []string{delAddr}
+	for _, op := range withdrawalAddrSets {
+		payoutAddrs = append(payoutAddrs, op.DstAddress)
+	}
+
+	deltas, err := db.GetELWithdrawalDeltas(s.dbOperator, payoutAddrs, indexer.WithdrawalKindUnstake)
+	if err != nil {
+		return nil, err
+	}
+
+	var unbondingTime time.Duration
+	if stakingParamsResp, err := GetStakingParams(s.conf.Blockchain.StoryAPIEndpoint); err != nil {
+		logger.Warn().Err(err).Msg("failed to get staking params, completion time of matured unbondings is not estimated")
+	} else if unbondingTime, err = time.ParseDuration(stakingParamsResp.Msg.Params.UnbondingTime); err != nil {
+		logger.Warn().Err(err).Str("unbonding_time", stakingParamsResp.Msg.Params.UnbondingTime).Msg("failed to parse unbonding time")
+	}
+
+	unbondings := make([]*unbonding, 0, len(unstakes))
+	for _, op := range unstakes {
+		amount := operationAmount(op)
+		data := &UnbondingData{
+			TxHash:           op.TxHash,
+			ValidatorAddress: op.DstValidatorAddress,
+			Amount:           amount.String(),
+			CreationHeight:   op.CLBlockHeight,
+			CreationTime:     op.CLBlockTime.Unix(),
+		}
+		if unbondingTime > 0 {
+			data.CompletionTime = op.CLBlockTime.Add(unbondingTime).Unix()
+		}
+
+		unbondings = append(unbondings, &unbonding{data: data, amount: amount, paid: new(big.Int)})
+	}
+
+	// The entries still unbonding carry the exact completion time and the balance left after slashing.
+	entries, err := s.getUnbondingEntries(delAddr)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to get unbonding delegations, only indexed unstakes are tracked")
+	}
+	for _, entry := range entries {
+		u := matchUnbonding(unbondings, entry.creationHeight, entry.validatorAddress)
+		if u == nil {
+			// Not sent by the delegator itself or not indexed yet.
+			u = &unbonding{
+				data: &UnbondingData{
+					ValidatorAddress: entry.validatorAddress,
+					CreationHeight:   entry.creationHeight,
+				},
+				paid: new(big.Int),
+			}
+			if unbondingTime > 0 {
+				u.data.CreationTime = entry.completionTime.Add(-unbondingTime).Unix()
+			}
+			unbondings = append(unbondings, u)
+		}
+
+		u.matched = true
+		u.amount = entry.balance
+		u.data.Amount = entry.balance.String()
+		u.data.CompletionTime = entry.completionTime.Unix()
+	}
+
+	if err := s.slashUnbondings(logger, unbondings); err != nil {
+		return nil, err
+	}
+
+	for _, u := range unbondings {
+		u.payoutAddr = withdrawalAddressAt(delAddr, withdrawalAddrSets, u.data.CompletionTime)
+	}
+
+	slices.SortStableFunc(unbondings, func(a, b *unbonding) int {
+		if c := cmp.Compare(a.data.CompletionTime, b.data.CompletionTime); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.data.CreationHeight, b.data.CreationHeight)
+	})
+
+	payUnbondings(unbondings, deltas)
+
+	now := time.Now().Unix()
+	res := make([]*UnbondingData, 0, len(unbondings))
+	for _, u := range unbondings {
+		switch {
+		case u.data.PaidOutHeight > 0:
+			u.data.State = UnbondingStatePaidOut
+		case u.matched || u.data.CompletionTime == 0 || u.data.CompletionTime > now:
+			u.data.State = UnbondingStateUnbonding
+		default:
+			u.data.State = UnbondingStateMatured
+		}
+		u.data.PaidOutAmount = u.paid.String()
+
+		res = append(res, u.data)
+	}
+
+	return res, nil
+}
+
+// operationAmount returns the amount in gwei the operation moved on the consensus layer.
+func operationAmount(op *db.SucceededOperation) *big.Int {
+	amount, ok := new(big.Int).SetString(op.Amount, 10)
+	if !ok {
+		// The consensus layer event has no amount, fall back to the requested one.
+		amount, _ = new(big.Int).SetString(op.StakeAmount, 10)
+		if amount == nil {
+			amount = new(big.Int)
+		}
+		amount.Div(amount, gweiPerWei)
+	}
+
+	return amount
+}
+
+// matchUnbonding returns the unmatched unbonding created at the height, preferring the one of the validator.
+func matchUnbonding(unbondings []*unbonding, creationHeight int64, valAddr string) *unbonding {
+	var match *unbonding
+	for _, u := range unbondings {
+		if u.matched || u.data.CreationHeight != creationHeight {
+			continue
+		}
+
+		if strings.EqualFold(u.data.ValidatorAddress, valAddr) {
+			return u
+		} else if match == nil {
+			match = u
+		}
+	}
+
+	return match
+}
+
+// slashUnbondings applies the double sign slashes of their validators to the unbondings no longer held by the story
+// api, whose amount is the one unstaked. The infraction height is not indexed, the unbondings going on at the slash
+// are assumed to have started after the infraction. Downtime is slashed at the infraction and spares them.
+func (s *Server) slashUnbondings(logger zerolog.Logger, unbondings []*unbonding) error {
+	valAddrs := make([]string, 0)
+	for _, u := range unbondings {
+		if !u.matched && u.data.CompletionTime > 0 {
+			valAddrs = append(valAddrs, u.data.ValidatorAddress)
+		}
+	}
+	if len(valAddrs) == 0 {
+		return nil
+	}
+
+	slashes, err := db.GetCLValidatorPenaltiesOf(s.dbOperator, valAddrs, indexer.PenaltyKindSlash)
+	if err != nil {
+		return err
+	} else if len(slashes) == 0 {
+		return nil
+	}
+
+	params, err := s.getSlashingParams()
+	if err != nil {
+		return err
+	}
+
+	fraction, err := decimal.NewFromString(params.SlashFractionDoubleSign)
+	if err != nil {
+		logger.Warn().Err(err).Msg("slash fraction unknown, amount of slashed unbondings is not adjusted")
+		return nil
+	}
+
+	for _, u := range unbondings {
+		if u.matched || u.data.CompletionTime == 0 {
+			continue
+		}
+
+		initial := decimal.NewFromBigInt(u.amount, 0)
+		for _, slash := range slashes {
+			if slash.Reason != indexer.PenaltyReasonDoubleSign || !strings.EqualFold(slash.EVMAddress, u.data.ValidatorAddress) ||
+				slash.BlockHeight < u.data.CreationHeight || slash.BlockTime.Unix() >= u.data.CompletionTime {
+				continue
+			}
+
+			u.amount.Sub(u.amount, initial.Mul(fraction).BigInt())
+			if u.amount.Sign() < 0 {
+				u.amount.SetInt64(0)
+			}
+		}
+		u.data.Amount = u.amount.String()
+	}
+
+	return nil
+}
+
+// withdrawalAddressAt returns the withdrawal address in effect at the unix timestamp from the successful withdrawal
+// address changes in execution order, the delegator itself until one is set.
+func withdrawalAddressAt(delAddr string, withdrawalAddrSets []*db.SucceededOperation, timestamp int64) string {
+	addr := delAddr
+	for _, op := range withdrawalAddrSets {
+		if op.CLBlockTime.Unix() > timestamp {
+			break
+		}
+		addr = op.DstAddress
+	}
+
+	return addr
+}
+
+// payUnbondings assigns the unstake withdrawals in block order to the unbondings in completion order. A withdrawal
+// only pays the unbondings completed before it and paid out to its address, the unbondings without a known
+// completion time are never paid. The amount left after paying them all is not attributed.
+func payUnbondings(unbondings []*unbonding, deltas []*db.ELWithdrawalDelta) {
+	queues := make(map[string][]*unbonding)
+	for _, u := range unbondings {
+		if u.data.CompletionTime > 0 {
+			queues[u.payoutAddr] = append(queues[u.payoutAddr], u)
+		}
+	}
+
+	for _, d := range deltas {
+		left, ok := new(big.Int).SetString(d.Amount, 10)
+		if !ok {
+			continue
+		}
+
+		queue := queues[d.Address]
+		for len(queue) > 0 && left.Sign() > 0 {
+			u := queue[0]
+			if u.data.CompletionTime > d.BlockTime {
+				break
+			}
+
+			pay := new(big.Int).Sub(u.amount, u.paid)
+			if pay.Cmp(left) > 0 {
+				pay.Set(left)
+			}
+			u.paid.Add(u.paid, pay)
+			left.Sub(left, pay)
+
+			if u.paid.Cmp(u.amount) >= 0 {
+				u.data.PaidOutHeight = d.BlockHeight
+				u.data.PaidOutTime = d.BlockTime
+				queue = queue[1:]
+			}
+		}
+		queues[d.Address] = queue
+	}
+}
+
+type unbondingEntry struct {
+	validatorAddress string
+	creationHeight   int64
+	completionTime   time.Time
+	balance          *big.Int
+}
+
+// getUnbondingEntries returns all the unbonding entries of the delegator from the story api.
+func (s *Server) getUnbondingEntries(delAddr string) ([]unbondingEntry, error) {
+	var (
+		entries []unbondingEntry
+		params  = map[string]string{}
+	)
+	for {
+		resp, err := GetStakingDelegatorUnbondingDelegations(s.conf.Blockchain.StoryAPIEndpoint, delAddr, params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ubd := range resp.Msg.UnbondingResponses {
+			for _, e := range ubd.Entries {
+				creationHeight, err := strconv.ParseInt(e.CreationHeight, 10, 64)
+				if err != nil {
+					return nil, err
+				}
+
+				completionTime, err := time.Parse(time.RFC3339Nano, e.CompletionTime)
+				if err != nil {
+					return nil, err
+				}
+
+				balance, ok := new(big.Int).SetString(e.Balance, 10)
+				if !ok {
+					return nil, ErrParseParameter
+				}
+
+				entries = append(entries, unbondingEntry{
+					validatorAddress: strings.ToLower(ubd.ValidatorAddress),
+					creationHeight:   creationHeight,
+					completionTime:   completionTime,
+					balance:          balance,
+				})
+			}
+		}
+
+		if resp.Msg.Pagination.NextKey == "" {
+			return entries, nil
+		}
+		params["pagination.key"] = resp.Msg.Pagination.NextKey
+	}
+}
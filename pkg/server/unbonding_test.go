@@ -0,0 +1,67 @@
+package server
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/piplabs/story-staking-api/db"
+)
+
+func TestPayUnbondings(t *testing.T) {
+	newUnbonding := func(amount, completionTime int64, payoutAddr string) *unbonding {
+		return &unbonding{
+			data:       &UnbondingData{CompletionTime: completionTime},
+			amount:     big.NewInt(amount),
+			paid:       new(big.Int),
+			payoutAddr: payoutAddr,
+		}
+	}
+
+	first := newUnbonding(100, 10, "0xdel1")
+	second := newUnbonding(50, 20, "0xdel1")
+	elsewhere := newUnbonding(30, 15, "0xpayout")
+	unknown := newUnbonding(40, 0, "0xdel1")
+
+	payUnbondings([]*unbonding{first, elsewhere, second, unknown}, []*db.ELWithdrawalDelta{
+		// Before any completion, left unattributed.
+		{Address: "0xdel1", BlockHeight: 1, BlockTime: 5, Amount: "10"},
+		// Pays the first in full and the second in part, the second completes later.
+		{Address: "0xdel1", BlockHeight: 2, BlockTime: 12, Amount: "120"},
+		{Address: "0xpayout", BlockHeight: 3, BlockTime: 16, Amount: "30"},
+		// Pays what is left of the second, the rest is not attributed.
+		{Address: "0xdel1", BlockHeight: 4, BlockTime: 25, Amount: "70"},
+		{Address: "0xdel1", BlockHeight: 5, BlockTime: 26, Amount: "invalid"},
+	})
+
+	require.Equal(t, "100", first.paid.String())
+	require.Equal(t, int64(2), first.data.PaidOutHeight)
+	require.Equal(t, int64(12), first.data.PaidOutTime)
+
+	require.Equal(t, "50", second.paid.String())
+	require.Equal(t, int64(4), second.data.PaidOutHeight)
+
+	require.Equal(t, "30", elsewhere.paid.String())
+	require.Equal(t, int64(3), elsewhere.data.PaidOutHeight)
+
+	require.Equal(t, "0", unknown.paid.String())
+	require.Equal(t, int64(0), unknown.data.PaidOutHeight)
+}
+
+func TestPayUnbondingsPartially(t *testing.T) {
+	u := &unbonding{
+		data:       &UnbondingData{CompletionTime: 10},
+		amount:     big.NewInt(100),
+		paid:       new(big.Int),
+		payoutAddr: "0xdel1",
+	}
+
+	payUnbondings([]*unbonding{u}, []*db.ELWithdrawalDelta{
+		{Address: "0xdel1", BlockHeight: 1, BlockTime: 11, Amount: "60"},
+		{Address: "0xother", BlockHeight: 2, BlockTime: 12, Amount: "40"},
+	})
+
+	require.Equal(t, "60", u.paid.String())
+	require.Equal(t, int64(0), u.data.PaidOutHeight)
+}
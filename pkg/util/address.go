@@ -1,8 +1,11 @@
 package util
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strings"
 
+	cmtsecp256k1 "github.com/cometbft/cometbft/crypto/secp256k1"
 	"github.com/decred/dcrd/dcrec/secp256k1"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -20,3 +23,102 @@ func CmpPubKeyToEVMAddress(pubKey []byte) (common.Address, error) {
 
 	return crypto.PubkeyToAddress(*uncmpPubKey), nil
 }
+
+// CmpPubKeyToCometAddress returns the consensus address of a validator, in the upper case hex format
+// used by CometBFT.
+func CmpPubKeyToCometAddress(pubKey []byte) (string, error) {
+	if len(pubKey) != secp256k1.PubKeyBytesLenCompressed {
+		return "", fmt.Errorf("invalid compressed public key length: %d", len(pubKey))
+	}
+
+	return cmtsecp256k1.PubKey(pubKey).Address().String(), nil
+}
+
+// ConsAddressToCometAddress converts the consensus address of a validator found in cosmos events, bech32
+// encoded with the valcons prefix or hex, to the upper case hex format used by CometBFT.
+func ConsAddressToCometAddress(consAddr string) (string, error) {
+	addr, err := hex.DecodeString(consAddr)
+	if err != nil {
+		if addr, err = decodeBech32(consAddr); err != nil {
+			return "", err
+		}
+	}
+
+	if len(addr) != 20 {
+		return "", fmt.Errorf("invalid consensus address length: %d", len(addr))
+	}
+
+	return strings.ToUpper(hex.EncodeToString(addr)), nil
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// decodeBech32 returns the data of a bech32 string after verifying its checksum.
+func decodeBech32(s string) ([]byte, error) {
+	s = strings.ToLower(s)
+
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 1 || sep+7 > len(s) {
+		return nil, fmt.Errorf("invalid bech32 string: %s", s)
+	}
+
+	hrp := s[:sep]
+	values := make([]byte, 0, len(hrp)*2+1+len(s)-sep-1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+
+	data := make([]byte, 0, len(s)-sep-1)
+	for _, c := range s[sep+1:] {
+		v := strings.IndexRune(bech32Charset, c)
+		if v < 0 {
+			return nil, fmt.Errorf("invalid bech32 character: %c", c)
+		}
+		data = append(data, byte(v))
+	}
+
+	if bech32Polymod(append(values, data...)) != 1 {
+		return nil, fmt.Errorf("invalid bech32 checksum: %s", s)
+	}
+
+	// Regroup the 5-bit groups without the checksum into bytes.
+	var (
+		acc  uint
+		bits uint
+		res  = make([]byte, 0, (len(data)-6)*5/8)
+	)
+	for _, v := range data[:len(data)-6] {
+		acc = acc<<5 | uint(v)
+		bits += 5
+		for bits >= 8 {
+			bits -= 8
+			res = append(res, byte(acc>>bits))
+		}
+	}
+	if bits >= 5 || acc&(1<<bits-1) != 0 {
+		return nil, fmt.Errorf("invalid bech32 padding: %s", s)
+	}
+
+	return res, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+
+	return chk
+}
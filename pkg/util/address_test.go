@@ -45,3 +45,45 @@ func TestCmpPubKeyToEVMAddress(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestCmpPubKeyToCometAddress(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		cmpPubKeyBase64 := "AqBVHHkyOfiie29Wrez6hMvC644kbZfPgXA1jFEs7Uwq"
+
+		cmpPubKey, err := base64.StdEncoding.DecodeString(cmpPubKeyBase64)
+		require.NoError(t, err)
+
+		cometAddr, err := util.CmpPubKeyToCometAddress(cmpPubKey)
+		require.NoError(t, err)
+		require.Equal(t, "0FC41199CE588948861A8DA86D725A5A073AE91A", cometAddr)
+	})
+
+	t.Run("invalid compressed public key length", func(t *testing.T) {
+		cmpPubKeyBase64 := "AqBVHHkyOfiie29Wrez6hMvC644kbZfPgXA1jFEs7Uwq"
+
+		cmpPubKey, err := base64.StdEncoding.DecodeString(cmpPubKeyBase64)
+		require.NoError(t, err)
+
+		_, err = util.CmpPubKeyToCometAddress(cmpPubKey[:len(cmpPubKey)-1])
+		require.Error(t, err)
+	})
+}
+
+func TestConsAddressToCometAddress(t *testing.T) {
+	t.Run("bech32", func(t *testing.T) {
+		cometAddr, err := util.ConsAddressToCometAddress("storyvalcons1plzprxwwtzy53ps63k5x6uj6tgrn46g6v5af2d")
+		require.NoError(t, err)
+		require.Equal(t, "0FC41199CE588948861A8DA86D725A5A073AE91A", cometAddr)
+	})
+
+	t.Run("hex", func(t *testing.T) {
+		cometAddr, err := util.ConsAddressToCometAddress("0fc41199ce588948861a8da86d725a5a073ae91a")
+		require.NoError(t, err)
+		require.Equal(t, "0FC41199CE588948861A8DA86D725A5A073AE91A", cometAddr)
+	})
+
+	t.Run("invalid checksum", func(t *testing.T) {
+		_, err := util.ConsAddressToCometAddress("storyvalcons1plzprxwwtzy53ps63k5x6uj6tgrn46g6v5af2q")
+		require.Error(t, err)
+	})
+}
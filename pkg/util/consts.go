@@ -1,5 +1,6 @@
 package util
 
 const (
-	UptimeWindow = 28800 // TODO: get slashing params from story API
+	// IncidentMinMissedBlocks is the number of consecutive missed blocks a streak needs to be a downtime incident.
+	IncidentMinMissedBlocks = 10
 )